@@ -0,0 +1,197 @@
+package changelog
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/educates/educatesenv/pkg/semver"
+)
+
+// breakingPattern matches text announcing a breaking change
+var breakingPattern = regexp.MustCompile(`(?i)\bbreaking\b|backwards?[- ]incompatib|\bnot backwards?[- ]compatible\b`)
+
+// ANSI escape sequences used to highlight breaking changes
+const (
+	highlightStart = "\x1b[1;31m"
+	highlightEnd   = "\x1b[0m"
+)
+
+// Release is a release and its notes
+type Release struct {
+	Tag         string
+	Name        string
+	Body        string
+	Prerelease  bool
+	PublishedAt time.Time
+}
+
+// RenderOptions controls how release notes are rendered
+type RenderOptions struct {
+	// Highlight marks sections and lines that mention breaking changes
+	Highlight bool
+	// BreakingOnly omits everything but the sections and lines that mention breaking changes
+	BreakingOnly bool
+	// Color highlights with ANSI colors rather than a text marker
+	Color bool
+}
+
+// ParseRange splits a range such as 3.1.0..3.2.0 into its ends, either of which may be
+// empty. It reports false if spec is a single version.
+func ParseRange(spec string) (string, string, bool) {
+	from, to, ok := strings.Cut(spec, "..")
+	return from, to, ok
+}
+
+// Select returns the releases after from, up to and including to, in ascending semantic
+// version order. An empty from starts at the oldest release and an empty to ends at the
+// newest. Pre-releases are only included if prerelease is set, and releases whose tags are
+// not semantic versions are ignored.
+func Select(releases []Release, from, to string, prerelease bool) ([]Release, error) {
+	var lower, upper *semver.Version
+	var err error
+	if from != "" {
+		if lower, err = semver.Parse(from); err != nil {
+			return nil, err
+		}
+	}
+	if to != "" {
+		if upper, err = semver.Parse(to); err != nil {
+			return nil, err
+		}
+	}
+	if lower != nil && upper != nil && lower.Compare(upper) >= 0 {
+		return nil, fmt.Errorf("%s is not older than %s", from, to)
+	}
+
+	var selected []Release
+	for _, rel := range releases {
+		v, err := semver.Parse(rel.Tag)
+		if err != nil {
+			continue
+		}
+		if (rel.Prerelease || v.IsPrerelease()) && !prerelease {
+			continue
+		}
+		if lower != nil && v.Compare(lower) <= 0 {
+			continue
+		}
+		if upper != nil && v.Compare(upper) > 0 {
+			continue
+		}
+		selected = append(selected, rel)
+	}
+	sort.SliceStable(selected, func(i, j int) bool {
+		return semver.Compare(selected[i].Tag, selected[j].Tag) < 0
+	})
+	return selected, nil
+}
+
+// Render writes the notes of releases under a heading for each release
+func Render(w io.Writer, releases []Release, opts RenderOptions) error {
+	for i, rel := range releases {
+		if i > 0 {
+			if _, err := fmt.Fprintln(w); err != nil {
+				return err
+			}
+		}
+
+		heading := "# " + rel.Tag
+		if rel.Name != "" && rel.Name != rel.Tag {
+			heading += " - " + rel.Name
+		}
+		if !rel.PublishedAt.IsZero() {
+			heading += " (" + rel.PublishedAt.Format(time.DateOnly) + ")"
+		}
+		if _, err := fmt.Fprintln(w, heading); err != nil {
+			return err
+		}
+
+		lines := renderBody(rel.Body, opts)
+		if len(lines) == 0 {
+			lines = []string{"", "No release notes."}
+			if opts.BreakingOnly {
+				lines = []string{"", "No breaking changes mentioned."}
+			}
+		}
+		for _, line := range lines {
+			if _, err := fmt.Fprintln(w, line); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// IsBreaking reports whether text mentions a breaking change
+func IsBreaking(text string) bool {
+	return breakingPattern.MatchString(text)
+}
+
+// renderBody returns the lines of a release body, highlighting or selecting sections whose
+// heading mentions a breaking change, and other lines that mention one
+func renderBody(body string, opts RenderOptions) []string {
+	var lines []string
+	scanner := bufio.NewScanner(strings.NewReader(strings.ReplaceAll(body, "\r\n", "\n")))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	// Markdown headings start sections, which end at the next heading of the same or a higher level
+	breakingLevel := 0
+	for scanner.Scan() {
+		line := scanner.Text()
+		if level := headingLevel(line); level > 0 {
+			if breakingLevel > 0 && level <= breakingLevel {
+				breakingLevel = 0
+			}
+			if breakingLevel == 0 && IsBreaking(line) {
+				breakingLevel = level
+			}
+		}
+
+		breaking := breakingLevel > 0 || IsBreaking(line)
+		switch {
+		case opts.BreakingOnly && !breaking:
+			continue
+		case (opts.Highlight || opts.BreakingOnly) && breaking && strings.TrimSpace(line) != "":
+			line = highlight(line, opts.Color)
+		}
+		lines = append(lines, line)
+	}
+	return trimBlankLines(lines)
+}
+
+// headingLevel returns the level of a markdown heading, or 0 if line is not a heading
+func headingLevel(line string) int {
+	level := len(line) - len(strings.TrimLeft(line, "#"))
+	if level == 0 || level > 6 || (len(line) > level && line[level] != ' ') {
+		return 0
+	}
+	return level
+}
+
+// highlight marks a line as describing a breaking change
+func highlight(line string, color bool) string {
+	if color {
+		return highlightStart + line + highlightEnd
+	}
+	return "! " + line
+}
+
+// trimBlankLines removes leading and trailing blank lines, keeping one leading blank line
+// to separate the notes from the release heading
+func trimBlankLines(lines []string) []string {
+	for len(lines) > 0 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	if len(lines) == 0 {
+		return nil
+	}
+	return append([]string{""}, lines...)
+}
@@ -0,0 +1,99 @@
+package changelog
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRange(t *testing.T) {
+	from, to, ok := ParseRange("3.1.0..3.2.0")
+	assert.True(t, ok)
+	assert.Equal(t, "3.1.0", from)
+	assert.Equal(t, "3.2.0", to)
+
+	from, to, ok = ParseRange("..3.2.0")
+	assert.True(t, ok)
+	assert.Empty(t, from)
+	assert.Equal(t, "3.2.0", to)
+
+	_, _, ok = ParseRange("3.2.0")
+	assert.False(t, ok)
+}
+
+func TestSelect(t *testing.T) {
+	releases := []Release{
+		{Tag: "3.2.0"}, {Tag: "3.0.0"}, {Tag: "3.10.0"}, {Tag: "3.1.0"},
+		{Tag: "3.2.0-rc.1", Prerelease: true}, {Tag: "nightly"},
+	}
+	tags := func(rels []Release) []string {
+		var result []string
+		for _, rel := range rels {
+			result = append(result, rel.Tag)
+		}
+		return result
+	}
+
+	selected, err := Select(releases, "3.0.0", "3.2.0", false)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"3.1.0", "3.2.0"}, tags(selected))
+
+	selected, err = Select(releases, "3.1.0", "", true)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"3.2.0-rc.1", "3.2.0", "3.10.0"}, tags(selected))
+
+	selected, err = Select(releases, "", "3.1.0", false)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"3.0.0", "3.1.0"}, tags(selected))
+
+	_, err = Select(releases, "3.2.0", "3.1.0", false)
+	assert.Error(t, err)
+	_, err = Select(releases, "main", "", false)
+	assert.Error(t, err)
+}
+
+func TestRender(t *testing.T) {
+	releases := []Release{{
+		Tag:         "3.2.0",
+		Name:        "Educates 3.2.0",
+		PublishedAt: time.Date(2025, 3, 4, 0, 0, 0, 0, time.UTC),
+		Body: "## Features\r\n\r\n* New workshop dashboard\r\n* Breaking: renamed session.name\r\n\r\n" +
+			"## Breaking changes\r\n\r\n* Removed the v1 API\r\n### Migration\r\n* Use v2\r\n\r\n## Fixes\r\n\r\n* Fixed login\r\n",
+	}, {Tag: "3.2.1"}}
+
+	var out bytes.Buffer
+	assert.NoError(t, Render(&out, releases, RenderOptions{Highlight: true}))
+	assert.Equal(t, `# 3.2.0 - Educates 3.2.0 (2025-03-04)
+
+## Features
+
+* New workshop dashboard
+! * Breaking: renamed session.name
+
+! ## Breaking changes
+
+! * Removed the v1 API
+! ### Migration
+! * Use v2
+
+## Fixes
+
+* Fixed login
+
+# 3.2.1
+
+No release notes.
+`, out.String())
+
+	out.Reset()
+	assert.NoError(t, Render(&out, releases, RenderOptions{BreakingOnly: true, Color: true}))
+	assert.Equal(t, "# 3.2.0 - Educates 3.2.0 (2025-03-04)\n\n"+
+		"\x1b[1;31m* Breaking: renamed session.name\x1b[0m\n"+
+		"\x1b[1;31m## Breaking changes\x1b[0m\n\n"+
+		"\x1b[1;31m* Removed the v1 API\x1b[0m\n"+
+		"\x1b[1;31m### Migration\x1b[0m\n"+
+		"\x1b[1;31m* Use v2\x1b[0m\n\n"+
+		"# 3.2.1\n\nNo breaking changes mentioned.\n", out.String())
+}
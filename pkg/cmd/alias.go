@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+var aliasCmd = &cobra.Command{
+	Use:   "alias",
+	Short: "Manage names for educates versions",
+	Long: `Aliases name a version, a version constraint or a development build, so that a
+version can be referred to by purpose. install, use and exec accept an alias wherever
+they accept a version. An alias to a constraint such as ~3.2 selects the newest
+matching version.`,
+	SilenceErrors: true,
+	SilenceUsage:  true,
+}
+
+var aliasSetCmd = &cobra.Command{
+	Use:           "set <name> <version|constraint|develop|dev:<name>>",
+	Short:         "Create or repoint an alias",
+	Args:          cobra.ExactArgs(2),
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := manager.SetAlias(args[0], args[1]); err != nil {
+			return fmt.Errorf("failed to set alias %s: %w", args[0], err)
+		}
+		fmt.Printf("Alias %s now points to %s\n", args[0], args[1])
+		return nil
+	},
+}
+
+var aliasRmCmd = &cobra.Command{
+	Use:               "rm <name>",
+	Aliases:           []string{"remove"},
+	Short:             "Remove an alias",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeAliases,
+	SilenceErrors:     true,
+	SilenceUsage:      true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := manager.RemoveAlias(args[0]); err != nil {
+			return fmt.Errorf("failed to remove alias %s: %w", args[0], err)
+		}
+		fmt.Printf("Removed alias %s\n", args[0])
+		return nil
+	},
+}
+
+var aliasListCmd = &cobra.Command{
+	Use:           "list",
+	Short:         "List aliases and the installed versions they resolve to",
+	Args:          cobra.NoArgs,
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		aliases, err := manager.Aliases()
+		if err != nil {
+			return err
+		}
+		if len(aliases) == 0 {
+			fmt.Println("No aliases defined")
+			return nil
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "NAME\tTARGET\tRESOLVES TO")
+		for _, alias := range aliases {
+			resolved, ok, err := manager.ResolveInstalled(alias.Name)
+			switch {
+			case err != nil:
+				resolved = err.Error()
+			case !ok:
+				resolved = "(not installed)"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\n", alias.Name, alias.Target, resolved)
+		}
+		return w.Flush()
+	},
+}
+
+// completeAliases completes the names of the defined aliases
+func completeAliases(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) != 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	aliases, err := manager.Aliases()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	var names []string
+	for _, alias := range aliases {
+		names = append(names, alias.Name)
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
+
+func init() {
+	aliasCmd.AddCommand(aliasSetCmd, aliasRmCmd, aliasListCmd)
+	rootCmd.AddCommand(aliasCmd)
+}
@@ -0,0 +1,147 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/educates/educatesenv/pkg/changelog"
+	"github.com/educates/educatesenv/pkg/semver"
+)
+
+var (
+	changelogHighlight    bool
+	changelogBreakingOnly bool
+	changelogPrerelease   bool
+)
+
+var changelogCmd = &cobra.Command{
+	Use:   "changelog <version>|<from>..<to>",
+	Short: "Show the release notes of a version or a range of versions",
+	Long: `Show the release notes of a version, or of every release after <from> up to and
+including <to> in semantic version order. An empty <from> starts at the active version,
+and an empty <to> ends at the newest release, so that
+
+  educatesenv changelog ..3.3.0
+
+shows what changes when switching from the active version to 3.3.0. Versions may be
+given as aliases or constraints.
+
+Use --highlight-breaking to mark sections and lines that mention breaking changes, or
+--breaking-only to show nothing else.`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeRemoteVersions,
+	SilenceErrors:     true,
+	SilenceUsage:      true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		releases, err := fetchReleaseNotes()
+		if err != nil {
+			return err
+		}
+
+		var selected []changelog.Release
+		if from, to, ok := changelog.ParseRange(args[0]); ok {
+			if from, to, err = resolveRange(from, to); err != nil {
+				return err
+			}
+			if selected, err = changelog.Select(releases, from, to, changelogPrerelease || isPrereleaseTag(from) || isPrereleaseTag(to)); err != nil {
+				return fmt.Errorf("invalid range %s: %w", args[0], err)
+			}
+			if len(selected) == 0 {
+				fmt.Printf("No releases between %s and %s\n", displayEnd(from, "the first release"), displayEnd(to, "the latest release"))
+				return nil
+			}
+		} else {
+			tag, err := resolveRelease(args[0])
+			if err != nil {
+				return err
+			}
+			for _, rel := range releases {
+				if rel.Tag == tag {
+					selected = append(selected, rel)
+				}
+			}
+			if len(selected) == 0 {
+				return fmt.Errorf("version %s not found. Run 'educatesenv list-remote' to see available versions", tag)
+			}
+		}
+
+		return changelog.Render(os.Stdout, selected, changelog.RenderOptions{
+			Highlight:    changelogHighlight,
+			BreakingOnly: changelogBreakingOnly,
+			Color:        colorEnabled(os.Stdout),
+		})
+	},
+}
+
+// fetchReleaseNotes returns the releases of the configured repository with their notes
+func fetchReleaseNotes() ([]changelog.Release, error) {
+	releases, err := gh.ListReleases()
+	if err != nil {
+		return nil, err
+	}
+	var notes []changelog.Release
+	for _, rel := range releases {
+		notes = append(notes, changelog.Release{
+			Tag:         rel.GetTagName(),
+			Name:        rel.GetName(),
+			Body:        rel.GetBody(),
+			Prerelease:  rel.GetPrerelease(),
+			PublishedAt: rel.GetPublishedAt().Time,
+		})
+	}
+	return notes, nil
+}
+
+// resolveRange resolves aliases and constraints at the ends of a range. An empty start is
+// replaced by the active version, if a release is active.
+func resolveRange(from, to string) (string, string, error) {
+	var err error
+	if from == "" {
+		if active, err := manager.ActiveVersion(); err == nil && active != "" {
+			if _, err := semver.Parse(active); err == nil {
+				from = active
+			}
+		}
+	} else if from, err = resolveRelease(from); err != nil {
+		return "", "", err
+	}
+	if to != "" {
+		if to, err = resolveRelease(to); err != nil {
+			return "", "", err
+		}
+	}
+	return from, to, nil
+}
+
+// isPrereleaseTag reports whether a range end names a pre-release, which opts the range
+// into pre-releases
+func isPrereleaseTag(tag string) bool {
+	v, err := semver.Parse(tag)
+	return err == nil && v.IsPrerelease()
+}
+
+// displayEnd describes a range end for messages
+func displayEnd(tag, open string) string {
+	if tag == "" {
+		return open
+	}
+	return tag
+}
+
+// colorEnabled reports whether output to f should be colored: only when f is a terminal
+// and NO_COLOR is not set
+func colorEnabled(f *os.File) bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	return isTerminal(f)
+}
+
+func init() {
+	changelogCmd.Flags().BoolVarP(&changelogHighlight, "highlight-breaking", "b", false, "Highlight sections and lines that mention breaking changes")
+	changelogCmd.Flags().BoolVar(&changelogBreakingOnly, "breaking-only", false, "Show only sections and lines that mention breaking changes")
+	changelogCmd.Flags().BoolVar(&changelogPrerelease, "pre", false, "Include pre-releases in a range")
+	rootCmd.AddCommand(changelogCmd)
+}
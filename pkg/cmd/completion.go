@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/educates/educatesenv/pkg/paths"
+	"github.com/educates/educatesenv/pkg/shell"
+)
+
+// releaseCacheTTL is how long release tags fetched for completion are reused
+const releaseCacheTTL = 10 * time.Minute
+
+var completionCmd = &cobra.Command{
+	Use:   "completion [bash|zsh|fish|powershell]",
+	Short: "Generate the autocompletion script for the specified shell",
+	Long: `Generate the autocompletion script for educatesenv for the specified shell.
+
+'educatesenv env' already registers completion, so this is only needed if you set up
+your PATH yourself.
+
+Bash (requires the bash-completion package):
+  # Load in the current session
+  eval "$(educatesenv completion bash)"
+  # Load for every session on Linux
+  educatesenv completion bash > /etc/bash_completion.d/educatesenv
+  # Load for every session on macOS with Homebrew
+  educatesenv completion bash > $(brew --prefix)/etc/bash_completion.d/educatesenv
+
+Zsh:
+  # Enable completion once, if not already done
+  echo "autoload -U compinit; compinit" >> ~/.zshrc
+  # Load for every session
+  educatesenv completion zsh > "${fpath[1]}/_educatesenv"
+
+Fish:
+  # Load in the current session
+  educatesenv completion fish | source
+  # Load for every session
+  educatesenv completion fish > ~/.config/fish/completions/educatesenv.fish
+
+PowerShell:
+  # Load in the current session
+  educatesenv completion powershell | Out-String | Invoke-Expression
+  # Load for every session by adding the line above to your $PROFILE
+
+Start a new shell for the changes to take effect.`,
+	ValidArgs:             shell.Supported(),
+	Args:                  cobra.ExactArgs(1),
+	DisableFlagsInUseLine: true,
+	SilenceErrors:         true,
+	SilenceUsage:          true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		sh, err := shell.Parse(args[0])
+		if err != nil {
+			return err
+		}
+
+		switch sh {
+		case shell.Bash:
+			return rootCmd.GenBashCompletionV2(os.Stdout, true)
+		case shell.Zsh:
+			return rootCmd.GenZshCompletion(os.Stdout)
+		case shell.Fish:
+			return rootCmd.GenFishCompletion(os.Stdout, true)
+		default:
+			return rootCmd.GenPowerShellCompletionWithDesc(os.Stdout)
+		}
+	},
+}
+
+// completeInstalledVersions completes the versions installed locally, the development
+// builds and the aliases
+func completeInstalledVersions(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) != 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	versions, err := manager.InstalledVersions()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	for _, build := range manager.DevBuilds() {
+		versions = append(versions, build.Version)
+	}
+	aliases, _ := completeAliases(cmd, args, toComplete)
+	return append(versions, aliases...), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeRemoteVersions completes release tags from GitHub. Releases are cached locally
+// for a short time so that completion does not query GitHub on every keypress.
+func completeRemoteVersions(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) != 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	releases, err := gh.ListReleasesCached(filepath.Join(paths.Resolve().CacheDir(), "releases.json"), releaseCacheTTL)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	var versions []string
+	for _, rel := range releases {
+		versions = append(versions, rel.Tag)
+	}
+	return versions, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveKeepOrder
+}
+
+func init() {
+	rootCmd.AddCommand(completionCmd)
+}
@@ -4,11 +4,13 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"text/tabwriter"
 
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v3"
 
 	"github.com/educates/educatesenv/pkg/config"
+	"github.com/educates/educatesenv/pkg/paths"
 )
 
 var configCmd = &cobra.Command{
@@ -26,12 +28,9 @@ var configInitCmd = &cobra.Command{
 	// Disable automatic usage printing on error
 	SilenceUsage: true,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		home, err := os.UserHomeDir()
-		if err != nil {
-			home = "."
-		}
-		configDir := filepath.Join(home, config.ConfigDirName)
-		configPath := filepath.Join(configDir, "config.yaml")
+		layout := paths.Resolve()
+		configDir := layout.Config
+		configPath := layout.ConfigFile()
 
 		// Check if config already exists
 		if _, err := os.Stat(configPath); err == nil {
@@ -61,12 +60,22 @@ var configInitCmd = &cobra.Command{
 	},
 }
 
+var configViewOrigin bool
+
 var configViewCmd = &cobra.Command{
-	Use:           "view",
-	Short:         "Show the current configuration",
+	Use:   "view",
+	Short: "Show the current configuration",
+	Long: `Show the configuration in use, merged from the system, user and project config files,
+environment variables and the selected profile. With --origin, show where each setting
+comes from instead.`,
+	Args:          cobra.NoArgs,
 	SilenceErrors: true,
 	SilenceUsage:  true,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if configViewOrigin {
+			return printOrigins()
+		}
+
 		// Convert config struct to YAML
 		yamlBytes, err := yaml.Marshal(cfg)
 		if err != nil {
@@ -80,8 +89,160 @@ var configViewCmd = &cobra.Command{
 	},
 }
 
+// printOrigins prints the config files that were loaded, and the value of each setting
+// with where it comes from
+func printOrigins() error {
+	fmt.Println("Config files:")
+	if len(cfg.Files()) == 0 {
+		fmt.Println("  none")
+	}
+	for _, f := range cfg.Files() {
+		fmt.Printf("  %-8s %s\n", f.Scope, f.Path)
+	}
+	fmt.Printf("Profile: %s\n\n", profileName(cfg.Profile))
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "KEY\tVALUE\tORIGIN")
+	for _, key := range config.Settings() {
+		value := cfg.Value(key)
+		if key == "github.token" && value != "" {
+			value = "(set)"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", key, value, cfg.Origin(key))
+	}
+	return w.Flush()
+}
+
+var configValidateCmd = &cobra.Command{
+	Use:   "validate [file]",
+	Short: "Check config files for unknown keys and values of the wrong type",
+	Long: `Check a config file, by default each of the system, user and project config files in
+use, against the schema of the config file. Every unknown key, value of the wrong type and
+setting the file cannot set is reported with its line number. A file written for an
+earlier apiVersion is valid if it is valid once upgraded.`,
+	Args:          cobra.MaximumNArgs(1),
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var files []string
+		if len(args) == 1 {
+			files = append(files, args[0])
+		} else {
+			for _, f := range cfg.Files() {
+				files = append(files, f.Path)
+			}
+			if len(files) == 0 {
+				files = append(files, config.ConfigFile())
+			}
+		}
+
+		for _, path := range files {
+			apiVersion, err := config.ValidateFile(path)
+			if err != nil {
+				return err
+			}
+			if apiVersion != config.CurrentAPIVersion {
+				fmt.Printf("%s is valid, and is upgraded to apiVersion %s when loaded\n", path, config.CurrentAPIVersion)
+				continue
+			}
+			fmt.Printf("%s is valid\n", path)
+		}
+		return nil
+	},
+}
+
+var configTrustCmd = &cobra.Command{
+	Use:   "trust [file]",
+	Short: "Trust a project config file, so that its settings are used",
+	Long: `Trust a project config file, by default the nearest .educatesenv.yaml in the current
+directory or its parents. A project config file can change the bin directory put on your
+PATH and the repository versions are installed from, so it is ignored until you have
+reviewed and trusted it. A file that changes after it was trusted has to be trusted again.`,
+	Args:          cobra.MaximumNArgs(1),
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, err := projectConfigArg(args)
+		if err != nil {
+			return err
+		}
+		if _, err := config.ValidateFile(path); err != nil {
+			return err
+		}
+		if err := config.Trust(path); err != nil {
+			return fmt.Errorf("failed to trust %s: %w", path, err)
+		}
+		fmt.Printf("Trusted %s\n", path)
+		return nil
+	},
+}
+
+var configUntrustCmd = &cobra.Command{
+	Use:           "untrust [file]",
+	Short:         "Stop using the settings of a project config file",
+	Args:          cobra.MaximumNArgs(1),
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, err := projectConfigArg(args)
+		if err != nil {
+			return err
+		}
+		if err := config.Untrust(path); err != nil {
+			return fmt.Errorf("failed to untrust %s: %w", path, err)
+		}
+		fmt.Printf("No longer trusting %s\n", path)
+		return nil
+	},
+}
+
+// projectConfigArg returns the project config file given as an argument, or the nearest
+// one in the current directory or its parents
+func projectConfigArg(args []string) (string, error) {
+	if len(args) == 1 {
+		return filepath.Abs(args[0])
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("failed to get the current directory: %w", err)
+	}
+	path, found := paths.FindProjectConfig(wd)
+	if !found {
+		return "", fmt.Errorf("no %s in %s or its parents", paths.ProjectConfigName, wd)
+	}
+	return path, nil
+}
+
+var configSchemaCmd = &cobra.Command{
+	Use:   "schema",
+	Short: "Print a JSON Schema of the config file",
+	Long: `Print a JSON Schema of the config file, which editors can use to validate and complete
+it. For editors using the YAML language server, save it and reference it from the first
+line of the config file:
+
+  educatesenv config schema > ~/.config/educatesenv/config.schema.json
+
+  # yaml-language-server: $schema=config.schema.json`,
+	Args:          cobra.NoArgs,
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		schema, err := config.JSONSchema()
+		if err != nil {
+			return fmt.Errorf("failed to generate schema: %w", err)
+		}
+		fmt.Println(string(schema))
+		return nil
+	},
+}
+
 func init() {
 	configCmd.AddCommand(configInitCmd)
 	configCmd.AddCommand(configViewCmd)
+	configCmd.AddCommand(configValidateCmd)
+	configCmd.AddCommand(configSchemaCmd)
+	configCmd.AddCommand(configTrustCmd)
+	configCmd.AddCommand(configUntrustCmd)
+	configViewCmd.Flags().BoolVar(&configViewOrigin, "origin", false, "Show the config files in use and where each setting comes from")
 	rootCmd.AddCommand(configCmd)
 }
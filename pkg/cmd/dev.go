@@ -0,0 +1,241 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/educates/educatesenv/pkg/config"
+	"github.com/educates/educatesenv/pkg/paths"
+	"github.com/educates/educatesenv/pkg/version"
+)
+
+var devCmd = &cobra.Command{
+	Use:   "dev",
+	Short: "Manage development builds of educates",
+	Long: `Development builds are educates binaries built from local checkouts. Each build is
+named, and used like an installed version as dev:<name>:
+
+  educatesenv dev add main ~/src/educates/client-programs/bin/educates
+  educatesenv use dev:main
+
+or built from a checkout with dev build.
+
+The build in development.binaryLocation is available as develop. Builds are stored in
+the development.builds section of the config file, and are only available while
+development mode is enabled.`,
+	SilenceErrors: true,
+	SilenceUsage:  true,
+}
+
+var devAddCmd = &cobra.Command{
+	Use:           "add <name> <path>",
+	Short:         "Add or repoint a named development build",
+	Args:          cobra.ExactArgs(2),
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := strings.TrimPrefix(args[0], version.DevBuildPrefix)
+		if err := version.ValidateDevBuildName(name); err != nil {
+			return err
+		}
+		path, err := filepath.Abs(args[1])
+		if err != nil {
+			return fmt.Errorf("failed to resolve %s: %w", args[1], err)
+		}
+		if _, err := os.Stat(path); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %s does not exist yet\n", path)
+		}
+
+		if err := registerDevBuild(name, path); err != nil {
+			return err
+		}
+		fmt.Printf("Added development build %s%s -> %s\n", version.DevBuildPrefix, name, path)
+		return nil
+	},
+}
+
+var (
+	devBuildName  string
+	devBuildForce bool
+	devBuildUse   bool
+)
+
+var devBuildCmd = &cobra.Command{
+	Use:   "build <checkout>",
+	Short: "Build the educates client from a source checkout",
+	Long: `Build the educates client from a git checkout of the educates repository and add it
+as a development build. The build is named after the checked out branch unless --name
+is given, and is stored in dev/<name> in the data directory, which is
+$XDG_DATA_HOME/educatesenv (~/.local/share/educatesenv), or $EDUCATESENV_ROOT if set.
+
+The client is built with go build in the client-programs directory, or with the shell
+command in development.buildCommand, which is run from the checkout root and must write
+the binary to $EDUCATESENV_BUILD_OUTPUT. Builds use the local Go toolchain and only
+modules already in the module cache.
+
+A build is skipped if the commit has not changed since the last build of the same name
+and the checkout has no uncommitted changes. Use --force to rebuild anyway.`,
+	Args:          cobra.ExactArgs(1),
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		info, rebuilt, err := manager.BuildDev(version.DevBuildOptions{Checkout: args[0], Name: devBuildName, Force: devBuildForce})
+		if err != nil {
+			return fmt.Errorf("failed to build %s: %w", args[0], err)
+		}
+		buildVersion := version.DevBuildVersion(info.Name)
+		if rebuilt {
+			fmt.Printf("Built %s at %s\n", buildVersion, info.Revision())
+		} else {
+			fmt.Printf("%s is up to date at %s\n", buildVersion, info.Revision())
+		}
+
+		if cfg.Development.Builds[info.Name] != info.Binary || !cfg.Development.Enabled {
+			if err := registerDevBuild(info.Name, info.Binary); err != nil {
+				return err
+			}
+		}
+
+		if devBuildUse {
+			if err := manager.UseVersion(buildVersion); err != nil {
+				return fmt.Errorf("failed to switch to development version: %w", err)
+			}
+			fmt.Printf("Now using educates development build %s from %s\n", buildVersion, info.Binary)
+		}
+		return nil
+	},
+}
+
+var devRmCmd = &cobra.Command{
+	Use:               "rm <name>",
+	Aliases:           []string{"remove"},
+	Short:             "Remove a named development build",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeDevBuilds,
+	SilenceErrors:     true,
+	SilenceUsage:      true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := strings.TrimPrefix(args[0], version.DevBuildPrefix)
+		if err := cfg.CheckUnlocked("development.builds"); err != nil {
+			return fmt.Errorf("failed to remove development build %s: %w", name, err)
+		}
+		// A build added while a profile is in use is in the profile
+		removed, err := config.UnsetValue(config.ConfigFile(), cfg.SettingPath("development", "builds", name))
+		if err == nil && !removed && cfg.Profile != "" {
+			removed, err = config.UnsetValue(config.ConfigFile(), []string{"development", "builds", name})
+		}
+		if err != nil {
+			return fmt.Errorf("failed to remove development build %s: %w", name, err)
+		}
+		if !removed {
+			return fmt.Errorf("development build %s is not defined in %s", name, config.ConfigFile())
+		}
+		fmt.Printf("Removed development build %s%s\n", version.DevBuildPrefix, name)
+
+		// Builds made by dev build are owned by educatesenv, so their files go too
+		if _, err := manager.RemoveDevBuild(name); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+
+		if active, err := manager.ActiveVersion(); err == nil && active == version.DevBuildVersion(name) {
+			fmt.Fprintf(os.Stderr, "Warning: %s is still active. Use `educatesenv use <version>` to switch to another version\n", active)
+		}
+		return nil
+	},
+}
+
+var devListCmd = &cobra.Command{
+	Use:           "list",
+	Short:         "List the development builds",
+	Args:          cobra.NoArgs,
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !cfg.Development.Enabled {
+			fmt.Println("Development mode is disabled. Enable it by setting development.enabled to true in the config file")
+			return nil
+		}
+		builds := manager.DevBuilds()
+		if len(builds) == 0 {
+			fmt.Println("No development builds defined")
+			return nil
+		}
+
+		active, err := manager.ActiveVersion()
+		if err != nil {
+			return fmt.Errorf("failed to determine active version: %w", err)
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "  VERSION\tPATH\tSTATUS\tCOMMIT")
+		for _, build := range builds {
+			marker := " "
+			if build.Version == active {
+				marker = "*"
+			}
+			status := "ok"
+			if _, err := os.Stat(build.Path); err != nil {
+				status = "missing"
+			}
+			commit := "-"
+			if info, err := manager.DevBuildInfo(build.Version); err == nil && info != nil && info.Binary == build.Path {
+				commit = info.Revision()
+			}
+			fmt.Fprintf(w, "%s %s\t%s\t%s\t%s\n", marker, build.Version, build.Path, status, commit)
+		}
+		return w.Flush()
+	},
+}
+
+// registerDevBuild adds a development build to the config file, enabling development mode
+// if it is disabled
+func registerDevBuild(name, path string) error {
+	if err := cfg.CheckUnlocked("development.builds"); err != nil {
+		return fmt.Errorf("failed to add development build %s: %w", name, err)
+	}
+	if err := cfg.CheckUnlocked("development.enabled"); err != nil && !cfg.Development.Enabled {
+		return fmt.Errorf("failed to enable development mode: %w", err)
+	}
+	configPath := config.ConfigFile()
+	// In portable mode, builds inside the tree are recorded relative to it so it can be moved
+	if err := config.SetValue(configPath, cfg.SettingPath("development", "builds", name), paths.Resolve().Rel(path)); err != nil {
+		return fmt.Errorf("failed to add development build %s: %w", name, err)
+	}
+	if cfg.Development.Builds == nil {
+		cfg.Development.Builds = map[string]string{}
+	}
+	cfg.Development.Builds[name] = path
+
+	if !cfg.Development.Enabled {
+		if err := config.SetValue(configPath, cfg.SettingPath("development", "enabled"), true); err != nil {
+			return fmt.Errorf("failed to enable development mode: %w", err)
+		}
+		cfg.Development.Enabled = true
+		fmt.Printf("Enabled development mode in %s\n", configPath)
+	}
+	return nil
+}
+
+// completeDevBuilds completes the names of the named development builds
+func completeDevBuilds(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) != 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	var names []string
+	for name := range cfg.Development.Builds {
+		names = append(names, name)
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
+
+func init() {
+	devBuildCmd.Flags().StringVar(&devBuildName, "name", "", "Name of the build, used as dev:<name> (default: the checked out branch)")
+	devBuildCmd.Flags().BoolVar(&devBuildForce, "force", false, "Rebuild even if the commit has not changed")
+	devBuildCmd.Flags().BoolVar(&devBuildUse, "use", false, "Set the build as active")
+	devCmd.AddCommand(devAddCmd, devBuildCmd, devRmCmd, devListCmd)
+	rootCmd.AddCommand(devCmd)
+}
@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/educates/educatesenv/pkg/shell"
+)
+
+var (
+	envShell      string
+	envCompletion bool
+	envHook       bool
+)
+
+var envCmd = &cobra.Command{
+	Use:   "env",
+	Short: "Print shell code that sets up PATH and completions for educatesenv",
+	Long: `Print shell code that sets up PATH and completions for educatesenv.
+
+Evaluate the output from your shell profile, for example:
+  bash/zsh:   eval "$(educatesenv env --shell bash)"
+  fish:       educatesenv env --shell fish | source
+  PowerShell: educatesenv env --shell powershell | Out-String | Invoke-Expression
+
+Run 'educatesenv init --modify-profile' to add this to your profile automatically.`,
+	Args:          cobra.NoArgs,
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return printEnv(envShell)
+	},
+}
+
+// printEnv writes the shell integration code for the given shell, or the detected one, to stdout
+func printEnv(name string) error {
+	sh := shell.Detect()
+	if name != "" {
+		var err error
+		if sh, err = shell.Parse(name); err != nil {
+			return err
+		}
+	}
+
+	code, err := shell.Env(sh, shell.EnvOptions{
+		Executable: executablePath(),
+		BinDir:     cfg.Local.Dir,
+		Completion: envCompletion,
+		Hook:       envHook,
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Print(code)
+	return nil
+}
+
+// executablePath returns the absolute path of the running educatesenv binary, falling back
+// to its name so that it is looked up on the PATH
+func executablePath() string {
+	exe, err := os.Executable()
+	if err != nil {
+		return "educatesenv"
+	}
+	return exe
+}
+
+func init() {
+	envCmd.Flags().StringVar(&envShell, "shell", "", "Shell to generate code for (bash, zsh, fish, powershell). Detected from $SHELL by default")
+	envCmd.Flags().BoolVar(&envCompletion, "completion", true, "Register shell completion for educatesenv")
+	envCmd.Flags().BoolVar(&envHook, "hook", false, "Wrap educatesenv in a shell function that refreshes the shell's command cache after each run")
+	rootCmd.AddCommand(envCmd)
+}
@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+
+	"github.com/spf13/cobra"
+)
+
+var execInstall bool
+
+var execCmd = &cobra.Command{
+	Use:   "exec <version|constraint|alias|dev:<name>> [--] [args...]",
+	Short: "Run a specific educates version without switching to it",
+	Long: `Run an installed educates version with the given arguments, leaving the active
+version untouched. The version may be a constraint such as ~3.2 or ">=3.1.0, <3.3.0",
+which runs the highest installed version satisfying it. With --install, a version that
+is not installed is installed first, without making it active.
+
+educatesenv exits with the exit code of educates. Use -- to separate the arguments for
+educates from flags for educatesenv, for example:
+
+  educatesenv exec 3.1.4 -- admin cluster delete`,
+	Args:              cobra.MinimumNArgs(1),
+	ValidArgsFunction: completeInstalledVersions,
+	SilenceErrors:     true,
+	SilenceUsage:      true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		spec, rest := args[0], args[1:]
+		// Flag parsing stops at the version, so a -- after it is passed through
+		if len(rest) > 0 && rest[0] == "--" {
+			rest = rest[1:]
+		}
+
+		v, err := resolveForExec(spec)
+		if err != nil {
+			return err
+		}
+		binary, err := manager.Binary(v)
+		if err != nil {
+			return fmt.Errorf("failed to run version %s: %w", v, err)
+		}
+		return runExecutable(binary, rest, os.Environ())
+	},
+}
+
+func init() {
+	execCmd.Flags().SetInterspersed(false)
+	execCmd.Flags().BoolVar(&execInstall, "install", false, "Install the version first if it is not installed")
+	rootCmd.AddCommand(execCmd)
+}
+
+// resolveForExec returns the installed version selected by spec, installing the newest
+// matching release first if --install is given
+func resolveForExec(spec string) (string, error) {
+	v, ok, err := manager.ResolveInstalled(spec)
+	if err != nil {
+		return "", err
+	}
+	if ok {
+		return v, nil
+	}
+	if !execInstall {
+		return "", fmt.Errorf("no installed version matches %s. Install one with `educatesenv install <version>` or pass --install", spec)
+	}
+
+	if v, err = resolveRelease(spec); err != nil {
+		return "", err
+	}
+	if err := manager.InstallVersion(v, false, false); err != nil {
+		return "", fmt.Errorf("failed to install version %s: %w", v, err)
+	}
+	return v, nil
+}
+
+// runExecutable runs path with args and env, passing through the standard streams, and
+// returns an ExitCodeError if it exits with a non-zero status. Interrupts are left to the
+// child, which receives them from the terminal too.
+func runExecutable(path string, args []string, env []string) error {
+	c := exec.Command(path, args...)
+	c.Env = env
+	c.Stdin = os.Stdin
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+
+	signal.Ignore(os.Interrupt)
+	defer signal.Reset(os.Interrupt)
+
+	if err := c.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			code := exitErr.ExitCode()
+			if code < 0 {
+				// Terminated by a signal
+				code = 1
+			}
+			return &ExitCodeError{Code: code}
+		}
+		return fmt.Errorf("failed to run %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var holdCmd = &cobra.Command{
+	Use:   "hold <version>",
+	Short: "Pin an installed version so that it is not upgraded or pruned",
+	Long: `Pin an installed version. upgrade does not move away from a held version, and prune
+never removes one. Use unhold to release it.`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeInstalledVersions,
+	SilenceErrors:     true,
+	SilenceUsage:      true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		resolved, installed, err := manager.ResolveInstalled(args[0])
+		if err != nil {
+			return err
+		}
+		if !installed {
+			return fmt.Errorf("version %s is not installed", args[0])
+		}
+		if err := manager.SetHeld(resolved, true); err != nil {
+			return fmt.Errorf("failed to hold version %s: %w", resolved, err)
+		}
+		fmt.Printf("Version %s is held\n", resolved)
+		return nil
+	},
+}
+
+var unholdCmd = &cobra.Command{
+	Use:               "unhold <version>",
+	Short:             "Release a held version",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeInstalledVersions,
+	SilenceErrors:     true,
+	SilenceUsage:      true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// A version that is no longer installed can still be released
+		resolved, installed, err := manager.ResolveInstalled(args[0])
+		if err != nil || !installed {
+			resolved = args[0]
+		}
+		if err := manager.SetHeld(resolved, false); err != nil {
+			return fmt.Errorf("failed to release version %s: %w", resolved, err)
+		}
+		fmt.Printf("Version %s is no longer held\n", resolved)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(holdCmd)
+	rootCmd.AddCommand(unholdCmd)
+}
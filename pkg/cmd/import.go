@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/educates/educatesenv/pkg/version"
+)
+
+var (
+	importAs        string
+	importLink      bool
+	importOverwrite bool
+	importCheck     bool
+	importUse       bool
+)
+
+var importCmd = &cobra.Command{
+	Use:   "import <file> --as <version>",
+	Short: "Install a local educates binary as a version",
+	Long: `Install an educates binary obtained out-of-band, such as one built by CI or copied
+into an air-gapped lab, as a version. The binary is copied into the bin directory, or
+symlinked with --link, and its checksum and origin are recorded so that it can be
+listed, verified and pruned like a downloaded version.
+
+With --check, the binary is run with the version command first, and the import fails
+unless it reports the version given with --as.`,
+	Args:          cobra.ExactArgs(1),
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		err := manager.ImportVersion(version.ImportOptions{
+			Source:    args[0],
+			Version:   importAs,
+			Link:      importLink,
+			Overwrite: importOverwrite,
+			Check:     importCheck,
+			Activate:  importUse,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to import %s: %w", args[0], err)
+		}
+		return nil
+	},
+}
+
+func init() {
+	importCmd.Flags().StringVar(&importAs, "as", "", "Version to install the binary as")
+	importCmd.Flags().BoolVar(&importLink, "link", false, "Symlink to the binary instead of copying it")
+	importCmd.Flags().BoolVar(&importOverwrite, "overwrite", false, "Replace the version if it is already installed")
+	importCmd.Flags().BoolVar(&importCheck, "check", false, "Check that the binary's version command reports the version")
+	importCmd.Flags().BoolVar(&importUse, "use", false, "Set the imported version as active")
+	_ = importCmd.MarkFlagRequired("as")
+	rootCmd.AddCommand(importCmd)
+}
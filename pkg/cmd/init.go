@@ -1,31 +1,47 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
-	"runtime"
+	"os"
 
 	"github.com/spf13/cobra"
 
 	"github.com/educates/educatesenv/pkg/config"
-	"github.com/educates/educatesenv/pkg/platform"
+	"github.com/educates/educatesenv/pkg/shell"
 )
 
 var (
 	downloadLatest bool
 	overwrite      bool
+	modifyProfile  bool
+	initShell      string
 )
 
 var initCmd = &cobra.Command{
-	Use:           "init",
-	Short:         "Initialize educatesenv: create default config and folders, and show PATH instructions",
+	Use:   "init [-]",
+	Short: "Initialize educatesenv: create default config and folders, and show PATH instructions",
+	Long: `Initialize educatesenv: create default config and folders, and show PATH instructions.
+
+With a single "-" argument, the folders are created silently and the shell code from
+'educatesenv env' is printed instead, so that 'eval "$(educatesenv init -)"' works.`,
+	Args:          cobra.MaximumNArgs(1),
 	SilenceErrors: true,
 	SilenceUsage:  true,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 1 && args[0] != "-" {
+			return fmt.Errorf("unexpected argument %q; only \"-\" is accepted", args[0])
+		}
+
 		_, defaultBin, configPath, configCreated, err := config.CreateConfigAndFolders()
 		if err != nil {
 			return err
 		}
 
+		if len(args) == 1 {
+			return printEnv(initShell)
+		}
+
 		if configCreated {
 			fmt.Printf("Config file created at %s\n", configPath)
 		} else {
@@ -33,24 +49,48 @@ var initCmd = &cobra.Command{
 		}
 		fmt.Printf("Bin directory ensured at %s\n", defaultBin)
 
-		// Print PATH instructions
-		fmt.Println("\nTo use educatesenv, add the bin directory to your PATH:")
-		var pathCmd string
-		switch runtime.GOOS {
-		case platform.Darwin, platform.Linux:
-			pathCmd = fmt.Sprintf("echo 'export PATH=\"%s:$PATH\"' >> ~/.bashrc\nsource ~/.bashrc", defaultBin)
-			fmt.Printf("\nFor bash/zsh, run:\n  %s\n", pathCmd)
-			fmt.Printf("\nFor fish shell, run:\n  set -U fish_user_paths %s $fish_user_paths\n", defaultBin)
-		case platform.Windows:
-			fmt.Printf("\nFor Windows (PowerShell), run:\n  [Environment]::SetEnvironmentVariable(\"Path\", \"%s;\" + $Env:Path, [EnvironmentVariableTarget]::User)\n", defaultBin)
-		default:
-			fmt.Printf("\nAdd %s to your PATH manually.\n", defaultBin)
+		sh := shell.Detect()
+		if initShell != "" {
+			if sh, err = shell.Parse(initShell); err != nil {
+				return err
+			}
+		}
+		snippet, err := shell.ProfileSnippet(sh, executablePath())
+		if err != nil {
+			return err
+		}
+
+		if modifyProfile {
+			home, err := os.UserHomeDir()
+			if err != nil {
+				return fmt.Errorf("failed to determine home directory: %w", err)
+			}
+			profile, err := shell.ProfilePath(sh, home)
+			if err != nil {
+				return err
+			}
+			changed, err := shell.UpdateProfile(profile, snippet)
+			if err != nil {
+				return err
+			}
+			if changed {
+				fmt.Printf("\nAdded educatesenv setup to %s\n", profile)
+			} else {
+				fmt.Printf("\neducatesenv setup already present in %s\n", profile)
+			}
+		} else {
+			// Print PATH instructions
+			fmt.Printf("\nTo use educatesenv, add the following line to your %s profile:\n  %s\n", sh, snippet)
+			fmt.Println("\nOr run 'educatesenv init --modify-profile' to add it for you. Use --shell to choose a different shell.")
+			if sh == shell.PowerShell {
+				fmt.Println("\nWithout Developer Mode or administrator rights, educatesenv activates versions on Windows by hard linking or copying educates.exe instead of symlinking it.")
+			}
 		}
 		fmt.Println("\nRestart your terminal or source your profile to apply the changes.")
 
 		if downloadLatest {
 			fmt.Println("\nFetching latest educates version...")
-			latest, err := gh.GetLatestReleaseVersion()
+			latest, err := gh.GetLatestReleaseVersion(context.Background())
 			if err != nil {
 				return fmt.Errorf("failed to get latest release version: %w", err)
 			}
@@ -70,5 +110,7 @@ var initCmd = &cobra.Command{
 func init() {
 	initCmd.Flags().BoolVar(&downloadLatest, "download", false, "Download and set as active the latest stable version")
 	initCmd.Flags().BoolVar(&overwrite, "overwrite", false, "Force download even if the version already exists")
+	initCmd.Flags().BoolVar(&modifyProfile, "modify-profile", false, "Add the educatesenv setup to your shell profile, replacing a previous educatesenv block")
+	initCmd.Flags().StringVar(&initShell, "shell", "", "Shell to set up (bash, zsh, fish, powershell). Detected from $SHELL by default")
 	rootCmd.AddCommand(initCmd)
 }
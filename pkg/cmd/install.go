@@ -2,31 +2,73 @@ package cmd
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 	"runtime"
+	"slices"
+	"sync"
+	"text/tabwriter"
 
 	"github.com/spf13/cobra"
 
+	"github.com/educates/educatesenv/pkg/lockfile"
+	"github.com/educates/educatesenv/pkg/paths"
 	"github.com/educates/educatesenv/pkg/platform"
+	"github.com/educates/educatesenv/pkg/version"
 )
 
 var (
 	useAfterInstall bool
 	forceOverwrite  bool
+	installJobs     int
 )
 
 var installCmd = &cobra.Command{
-	Use:           "install <version>",
-	Short:         "Install a specific version of educates",
-	Args:          cobra.ExactArgs(1),
-	SilenceErrors: true,
-	SilenceUsage:  true,
-	RunE: func(cmd *cobra.Command, args []string) error {
-		version := args[0]
+	Use:   "install [version|alias|constraint]...",
+	Short: "Install one or more versions of educates",
+	Long: `Install one or more versions of educates. When several versions are given, they are
+resolved against a single listing of the releases and downloaded concurrently, and a
+summary of the outcome for each version is printed. A failure to install one version does
+not stop the others.
 
+If the current directory or one of its parents has a lockfile (` + lockfile.FileName + `),
+the version it pins is installed from the exact binary it records, and the install fails
+if the binary's SHA-256 digest differs. Without arguments, the pinned version is installed.
+Other versions are installed as if there was no lockfile.`,
+	Args:              cobra.ArbitraryArgs,
+	ValidArgsFunction: completeRemoteVersions,
+	SilenceErrors:     true,
+	SilenceUsage:      true,
+	RunE: func(cmd *cobra.Command, args []string) error {
 		if !platform.IsSupportedPlatform(runtime.GOOS, runtime.GOARCH) {
 			return fmt.Errorf("unsupported platform: %s/%s", runtime.GOOS, runtime.GOARCH)
 		}
 
+		lock, err := projectLockfile()
+		if err != nil {
+			return err
+		}
+		if len(args) == 0 {
+			if lock == nil {
+				return fmt.Errorf("no version given and no %s found in the current directory or its parents", lockfile.FileName)
+			}
+			args = []string{lock.lf.Version}
+		}
+
+		if len(args) > 1 {
+			if useAfterInstall {
+				return fmt.Errorf("--use can only be given when installing a single version")
+			}
+			return installMany(args, installJobs, lock)
+		}
+
+		version, err := resolveRelease(args[0])
+		if err != nil {
+			return err
+		}
+		if err := lock.pin(version); err != nil {
+			return err
+		}
 		if err := manager.InstallVersion(version, forceOverwrite, useAfterInstall); err != nil {
 			return fmt.Errorf("failed to install version %s: %w", version, err)
 		}
@@ -35,8 +77,155 @@ var installCmd = &cobra.Command{
 	},
 }
 
+// projectLock is the lockfile of the project containing the current directory
+type projectLock struct {
+	path string
+	lf   *lockfile.Lockfile
+}
+
+// projectLockfile loads the lockfile of the project containing the current directory, if
+// there is one
+func projectLockfile() (*projectLock, error) {
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("failed to determine current directory: %w", err)
+	}
+	path, found := lockfile.Find(wd)
+	if !found {
+		return nil, nil
+	}
+	lf, err := lockfile.Load(path)
+	if err != nil {
+		return nil, err
+	}
+	return &projectLock{path: path, lf: lf}, nil
+}
+
+// pin pins the binary the lockfile records for installs if versions include the version
+// it pins. Installs of other versions are not affected by the lockfile, so that it does not
+// get in the way when its source or platforms differ.
+func (p *projectLock) pin(versions ...string) error {
+	if p == nil || !slices.Contains(versions, p.lf.Version) {
+		return nil
+	}
+	if err := manager.UseLockfile(p.lf); err != nil {
+		return fmt.Errorf("cannot use %s: %w", p.path, err)
+	}
+	fmt.Printf("Using %s, which pins educates %s\n", p.path, p.lf.Version)
+	return nil
+}
+
+// installMany installs several versions, downloading up to workers at once, and prints a
+// summary table. The version pinned by lock, if any, is installed from the binary it pins.
+func installMany(specs []string, workers int, lock *projectLock) error {
+	// List the releases at most once, however many constraints need resolving
+	var (
+		tags     []string
+		tagsErr  error
+		tagsRead bool
+	)
+	listTags := func() ([]string, error) {
+		if !tagsRead {
+			tags, tagsErr = releaseTags()
+			tagsRead = true
+		}
+		return tags, tagsErr
+	}
+
+	// Results are reported in the order the versions were given, each version once
+	results := make([]version.InstallResult, len(specs))
+	var versions []string
+	seen := map[string]bool{}
+	for i, spec := range specs {
+		v, err := manager.ResolveRelease(spec, listTags)
+		if err != nil {
+			results[i] = version.InstallResult{Version: spec, Status: version.InstallFailed, Err: err}
+			continue
+		}
+		results[i].Version = v
+		if !seen[v] {
+			seen[v] = true
+			versions = append(versions, v)
+		}
+	}
+
+	if err := lock.pin(versions...); err != nil {
+		return err
+	}
+
+	var mu sync.Mutex
+	installed, installErr := manager.InstallVersions(versions, forceOverwrite, workers, func(v, message string) {
+		mu.Lock()
+		defer mu.Unlock()
+		fmt.Printf("[%s] %s\n", v, message)
+	})
+	if installed == nil && installErr != nil {
+		return installErr
+	}
+	byVersion := map[string]version.InstallResult{}
+	for _, result := range installed {
+		byVersion[result.Version] = result
+	}
+	reported := map[string]bool{}
+	var summary []version.InstallResult
+	for _, result := range results {
+		if result.Status != version.InstallFailed {
+			if reported[result.Version] {
+				continue
+			}
+			reported[result.Version] = true
+			result = byVersion[result.Version]
+		}
+		summary = append(summary, result)
+	}
+
+	fmt.Println()
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tSTATUS\tERROR")
+	failed := 0
+	for _, result := range summary {
+		message := ""
+		if result.Err != nil {
+			failed++
+			message = result.Err.Error()
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", result.Version, result.Status, message)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if installErr != nil {
+		return installErr
+	}
+	if failed > 0 {
+		return fmt.Errorf("failed to install %d of %d versions", failed, len(summary))
+	}
+	return nil
+}
+
+// resolveRelease returns the release selected by spec, which is a version, release tag,
+// alias or constraint
+func resolveRelease(spec string) (string, error) {
+	return manager.ResolveRelease(spec, releaseTags)
+}
+
+// releaseTags returns the tags of the releases, from the local release cache if it is fresh
+func releaseTags() ([]string, error) {
+	releases, err := gh.ListReleasesCached(filepath.Join(paths.Resolve().CacheDir(), "releases.json"), releaseCacheTTL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list releases: %w", err)
+	}
+	var tags []string
+	for _, rel := range releases {
+		tags = append(tags, rel.Tag)
+	}
+	return tags, nil
+}
+
 func init() {
 	installCmd.Flags().BoolVar(&useAfterInstall, "use", false, "Set the installed version as active")
 	installCmd.Flags().BoolVar(&forceOverwrite, "overwrite", false, "Force download even if the version already exists")
+	installCmd.Flags().IntVarP(&installJobs, "jobs", "j", version.DefaultInstallWorkers, "Number of versions to download at once")
 	rootCmd.AddCommand(installCmd)
 }
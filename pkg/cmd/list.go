@@ -3,21 +3,24 @@ package cmd
 import (
 	"fmt"
 	"os"
-	"path/filepath"
 	"strings"
+	"text/tabwriter"
+	"time"
 
 	"github.com/educates/educatesenv/pkg/config"
 	"github.com/spf13/cobra"
 )
 
+var listLong bool
+
 var listCmd = &cobra.Command{
 	Use:           "list",
 	Short:         "List installed educates versions",
 	SilenceErrors: true,
 	SilenceUsage:  true,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		// Get all files in the bin directory
-		files, err := os.ReadDir(cfg.Local.Dir)
+		// Get all versions in the bin directory
+		versions, err := manager.InstalledVersions()
 		// If the bin directory doesn't exist call init
 		if err != nil {
 			_, _, _, _, err := config.CreateConfigAndFolders()
@@ -27,49 +30,57 @@ var listCmd = &cobra.Command{
 		}
 
 		// Get the active version by reading the symlink
-		activeVersion := ""
-		isDevelopmentActive := false
-		symlink := filepath.Join(cfg.Local.Dir, "educates")
-		if target, err := os.Readlink(symlink); err == nil {
-			// Check if the active version is a development version
-			if cfg.Development.Enabled && target == cfg.Development.BinaryLocation {
-				isDevelopmentActive = true
-			} else {
-				activeVersion = filepath.Base(target)
-				activeVersion = strings.TrimPrefix(activeVersion, "educates-")
-			}
+		activeVersion, err := manager.ActiveVersion()
+		if err != nil {
+			return fmt.Errorf("failed to determine active version: %w", err)
+		}
+
+		aliases, err := manager.AliasesByVersion()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+		held, err := manager.HeldVersions()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+
+		if listLong {
+			return printLongList(versions, activeVersion, aliases)
 		}
 
 		// Print installed versions
 		fmt.Println("Installed versions:")
 
-		// Show development version if enabled
-		if cfg.Development.Enabled {
-			if isDevelopmentActive {
-				fmt.Printf("* develop (active) -> %s\n", cfg.Development.BinaryLocation)
+		// Show development builds if enabled
+		devBuilds := manager.DevBuilds()
+		for _, build := range devBuilds {
+			if build.Version == activeVersion {
+				fmt.Printf("* %s (%s) -> %s\n", build.Version, versionNotes(true, aliases[build.Version]), build.Path)
+			} else if len(aliases[build.Version]) > 0 {
+				fmt.Printf("  %s (%s) -> %s\n", build.Version, versionNotes(false, aliases[build.Version]), build.Path)
 			} else {
-				fmt.Printf("  develop -> %s\n", cfg.Development.BinaryLocation)
+				fmt.Printf("  %s -> %s\n", build.Version, build.Path)
 			}
 		}
 
 		// Print regular versions
-		foundVersions := false
-		for _, file := range files {
-			if file.IsDir() || file.Name() == "educates" {
-				continue
+		for _, version := range versions {
+			notes := versionNotes(version == activeVersion, aliases[version])
+			if held[version] {
+				notes = strings.TrimPrefix(notes+", held", ", ")
+			}
+			marker := " "
+			if version == activeVersion {
+				marker = "*"
 			}
-			if strings.HasPrefix(file.Name(), "educates-") {
-				version := strings.TrimPrefix(file.Name(), "educates-")
-				if version == activeVersion {
-					fmt.Printf("* %s (active)\n", version)
-				} else {
-					fmt.Printf("  %s\n", version)
-				}
-				foundVersions = true
+			if notes != "" {
+				fmt.Printf("%s %s (%s)\n", marker, version, notes)
+			} else {
+				fmt.Printf("%s %s\n", marker, version)
 			}
 		}
 
-		if !foundVersions && !cfg.Development.Enabled {
+		if len(versions) == 0 && len(devBuilds) == 0 {
 			fmt.Println("No versions installed")
 		}
 
@@ -77,6 +88,73 @@ var listCmd = &cobra.Command{
 	},
 }
 
+// versionNotes describes whether a version is active and the aliases pointing at it
+func versionNotes(active bool, aliases []string) string {
+	var notes []string
+	if active {
+		notes = append(notes, "active")
+	}
+	if len(aliases) > 0 {
+		notes = append(notes, "aliases: "+strings.Join(aliases, ", "))
+	}
+	return strings.Join(notes, ", ")
+}
+
+// printLongList prints installed versions with their install records
+func printLongList(versions []string, activeVersion string, aliases map[string][]string) error {
+	records, err := manager.InstallRecords()
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "  VERSION\tINSTALLED\tSIZE\tSHA256\tEDUCATESENV\tSOURCE\tALIASES")
+	devBuilds := manager.DevBuilds()
+	for _, build := range devBuilds {
+		marker := " "
+		if build.Version == activeVersion {
+			marker = "*"
+		}
+		fmt.Fprintf(w, "%s %s\t-\t-\t-\t-\t%s\t%s\n", marker, build.Version, build.Path, strings.Join(aliases[build.Version], ","))
+	}
+	for _, version := range versions {
+		marker := " "
+		if version == activeVersion {
+			marker = "*"
+		}
+		record, ok := records[version]
+		if !ok {
+			fmt.Fprintf(w, "%s %s\t-\t-\t-\t-\t(not recorded)\t%s\n", marker, version, strings.Join(aliases[version], ","))
+			continue
+		}
+		source := record.Source
+		if record.Imported != "" {
+			source += " (imported: " + record.Imported + ")"
+		}
+		if record.Overwrite {
+			source += " (overwrite)"
+		}
+		fmt.Fprintf(w, "%s %s\t%s\t%d\t%s\t%s\t%s\t%s\n", marker, version, record.InstalledAt.Local().Format(time.DateTime), record.Size, shortDigest(record.SHA256), record.EducatesenvVersion, source, strings.Join(aliases[version], ","))
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if len(versions) == 0 && len(devBuilds) == 0 {
+		fmt.Println("No versions installed")
+	}
+	return nil
+}
+
+// shortDigest abbreviates a hex digest for display
+func shortDigest(digest string) string {
+	if len(digest) > 12 {
+		return digest[:12]
+	}
+	return digest
+}
+
 func init() {
+	listCmd.Flags().BoolVarP(&listLong, "long", "l", false, "Show install time, size, checksum and source of each version")
 	rootCmd.AddCommand(listCmd)
 }
@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/educates/educatesenv/pkg/lockfile"
+	"github.com/educates/educatesenv/pkg/version"
+)
+
+var lockCmd = &cobra.Command{
+	Use:   "lock <version|alias|constraint>",
+	Short: "Pin a release for the project in the current directory",
+	Long: `Write a lockfile (` + lockfile.FileName + `) in the current directory, pinning the release
+together with the name and SHA-256 digest of its binary for every supported platform.
+install run in the directory, or any directory below it, then downloads exactly these
+binaries and fails if a digest does not match. Each binary is downloaded to compute its
+digest. Commit the lockfile with the project.`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeRemoteVersions,
+	SilenceErrors:     true,
+	SilenceUsage:      true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		v, err := resolveRelease(args[0])
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Computing digests of the educates %s binaries...\n", v)
+		lf, err := manager.LockVersion(v, version.DefaultInstallWorkers)
+		if err != nil {
+			return fmt.Errorf("failed to lock version %s: %w", v, err)
+		}
+
+		wd, err := os.Getwd()
+		if err != nil {
+			return fmt.Errorf("failed to determine current directory: %w", err)
+		}
+		path := filepath.Join(wd, lockfile.FileName)
+		if err := lf.Save(path); err != nil {
+			return err
+		}
+
+		keys := make([]string, 0, len(lf.Assets))
+		for key := range lf.Assets {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "PLATFORM\tASSET\tSHA256")
+		for _, key := range keys {
+			fmt.Fprintf(w, "%s\t%s\t%s\n", key, lf.Assets[key].Name, lf.Assets[key].SHA256)
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+		fmt.Printf("Locked educates %s in %s\n", v, path)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(lockCmd)
+}
@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/educates/educatesenv/pkg/notifier"
+	"github.com/educates/educatesenv/pkg/paths"
+	"github.com/educates/educatesenv/pkg/version"
+)
+
+// notifyUpdate prints a notice to stderr when a release newer than the active version
+// exists. It only runs when enabled in the config, and never when the output may be read
+// by a program. Any failure is silently ignored, as the notice is never worth breaking a
+// command over.
+func notifyUpdate(cmd *cobra.Command) {
+	if !cfg.UpdateNotifier.Enabled || os.Getenv(notifier.DisableEnv) != "" {
+		return
+	}
+	// exec passes the output of the educates binary through untouched
+	if cmd == execCmd || cmd.Name() == "help" || cmd.Name() == cobra.ShellCompRequestCmd || cmd.Name() == cobra.ShellCompNoDescRequestCmd {
+		return
+	}
+	if output := cmd.Flags().Lookup("output"); output != nil && output.Value.String() == "json" {
+		return
+	}
+	if !isTerminal(os.Stdout) || !isTerminal(os.Stderr) {
+		return
+	}
+
+	current, err := manager.ActiveVersion()
+	if err != nil || current == "" || version.IsDevVersion(current) {
+		return
+	}
+
+	interval, err := version.ParseAge(cfg.UpdateNotifier.Interval)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: invalid updateNotifier.interval: %v\n", err)
+		return
+	}
+	// Profiles may use repositories of the same name on different servers
+	repository := cfg.Github.Org + "/" + cfg.Github.Repository
+	if cfg.Github.BaseURL != "" {
+		repository = strings.TrimSuffix(cfg.Github.BaseURL, "/") + "/" + repository
+	}
+	n := &notifier.Notifier{
+		StatePath:  paths.Resolve().UpdateCheckFile(),
+		Repository: repository,
+		Interval:   interval,
+		Fetch:      gh.GetLatestReleaseVersion,
+	}
+	latest, _ := n.Latest()
+	if message := notifier.Message(current, latest); message != "" {
+		fmt.Fprintln(os.Stderr, message)
+	}
+}
+
+// isTerminal reports whether f is a terminal
+func isTerminal(f *os.File) bool {
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
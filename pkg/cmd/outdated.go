@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/educates/educatesenv/pkg/semver"
+	"github.com/educates/educatesenv/pkg/version"
+)
+
+var outdatedCmd = &cobra.Command{
+	Use:   "outdated",
+	Short: "Show newer releases available for the installed versions",
+	Long: `Compare each installed version against the releases, showing the newest patch
+release (same minor version), minor release (same major version) and major release
+newer than it. Pre-releases are not offered.`,
+	Args:          cobra.NoArgs,
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		installed, err := manager.InstalledVersions()
+		if err != nil {
+			return err
+		}
+		activeVersion, err := manager.ActiveVersion()
+		if err != nil {
+			return fmt.Errorf("failed to determine active version: %w", err)
+		}
+		held, err := manager.HeldVersions()
+		if err != nil {
+			return err
+		}
+		tags, err := releaseTags()
+		if err != nil {
+			return err
+		}
+
+		semver.Sort(installed)
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "  VERSION\tPATCH\tMINOR\tMAJOR\tNOTES")
+		outdated := 0
+		for _, v := range installed {
+			upgrades, err := version.AvailableUpgrades(v, tags)
+			if err != nil {
+				// Imported builds may not be named by a semantic version
+				continue
+			}
+			if upgrades != (version.Upgrades{}) {
+				outdated++
+			}
+			marker := " "
+			if v == activeVersion {
+				marker = "*"
+			}
+			notes := ""
+			if held[v] {
+				notes = "held"
+			}
+			fmt.Fprintf(w, "%s %s\t%s\t%s\t%s\t%s\n", marker, v, orDash(upgrades.Patch), orDash(upgrades.Minor), orDash(upgrades.Major), notes)
+		}
+		if outdated == 0 {
+			fmt.Println("All installed versions are up to date")
+			return nil
+		}
+		return w.Flush()
+	},
+}
+
+// orDash returns s, or "-" if it is empty
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+func init() {
+	rootCmd.AddCommand(outdatedCmd)
+}
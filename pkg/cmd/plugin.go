@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/educates/educatesenv/pkg/config"
+	"github.com/educates/educatesenv/pkg/paths"
+	"github.com/educates/educatesenv/pkg/plugin"
+)
+
+var pluginCmd = &cobra.Command{
+	Use:   "plugin",
+	Short: "Manage educatesenv plugins",
+	Long: `Plugins are executables named educatesenv-<name> found in the plugins directory
+or on the PATH. The plugins directory is plugins in the data directory, which is
+$XDG_DATA_HOME/educatesenv (~/.local/share/educatesenv), or $EDUCATESENV_ROOT if set.
+Running "educatesenv <name>" runs the plugin with the remaining arguments and the
+following environment variables set:
+
+  EDUCATESENV_CONFIG           path of the educatesenv config file
+  EDUCATESENV_BIN_DIR          directory holding the installed educates versions
+  EDUCATESENV_ACTIVE_VERSION   the active version, "develop", or empty if none is active
+
+Global flags such as --profile may come before the plugin name. Built-in commands always
+take precedence over plugins of the same name.`,
+	SilenceErrors: true,
+	SilenceUsage:  true,
+}
+
+var pluginListCmd = &cobra.Command{
+	Use:           "list",
+	Short:         "List the plugins found in the plugins directory and on the PATH",
+	Args:          cobra.NoArgs,
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		plugins := plugin.Discover(plugin.SearchPath(paths.Resolve().PluginsDir()))
+		if len(plugins) == 0 {
+			fmt.Printf("No plugins found in %s or on the PATH.\n", paths.Resolve().PluginsDir())
+			return nil
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "NAME\tPATH\tNOTE")
+		for _, p := range plugins {
+			note := ""
+			switch {
+			case isBuiltinCommand(p.Name):
+				note = "overshadowed by built-in command"
+			case p.Shadowed:
+				note = "shadowed by an earlier plugin"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\n", p.Name, p.Path, note)
+		}
+		return w.Flush()
+	},
+}
+
+func init() {
+	pluginCmd.AddCommand(pluginListCmd)
+	rootCmd.AddCommand(pluginCmd)
+}
+
+// pluginInvocation returns the plugin name and arguments if args invoke a subcommand that
+// is not built in. Global flags such as --profile may come before the subcommand, and are
+// applied.
+func pluginInvocation(args []string) (string, []string, bool) {
+	flags := rootCmd.PersistentFlags()
+	i := 0
+	for i < len(args) && strings.HasPrefix(args[i], "--") {
+		name, _, hasValue := strings.Cut(strings.TrimPrefix(args[i], "--"), "=")
+		f := flags.Lookup(name)
+		if f == nil {
+			return "", nil, false
+		}
+		i++
+		// The value of a flag that is not a bool is the next argument, unless given with =
+		if !hasValue && f.NoOptDefVal == "" {
+			i++
+		}
+	}
+	if i >= len(args) || strings.HasPrefix(args[i], "-") || isBuiltinCommand(args[i]) {
+		return "", nil, false
+	}
+	if err := flags.Parse(args[:i]); err != nil {
+		return "", nil, false
+	}
+	return args[i], args[i+1:], true
+}
+
+// isBuiltinCommand reports whether name is a built-in subcommand, alias or help
+func isBuiltinCommand(name string) bool {
+	if name == "help" || name == cobra.ShellCompRequestCmd || name == cobra.ShellCompNoDescRequestCmd {
+		return true
+	}
+	for _, c := range rootCmd.Commands() {
+		if c.Name() == name || c.HasAlias(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// runPlugin runs a plugin with args and the educatesenv environment variables
+func runPlugin(p plugin.Plugin, args []string) error {
+	active, err := manager.ActiveVersion()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+
+	env := append(os.Environ(),
+		"EDUCATESENV_CONFIG="+config.ConfigFile(),
+		"EDUCATESENV_BIN_DIR="+cfg.Local.Dir,
+		"EDUCATESENV_ACTIVE_VERSION="+active,
+	)
+	return runExecutable(p.Path, args, env)
+}
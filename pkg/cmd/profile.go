@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/educates/educatesenv/pkg/config"
+)
+
+var profileCmd = &cobra.Command{
+	Use:   "profile",
+	Short: "Switch between named sets of GitHub, local and development settings",
+	Long: `Profiles are named sets of github, local and development settings in the profiles
+section of the config file, such as a GitHub Enterprise fork with its own token and bin
+directory:
+
+  profiles:
+    ghe:
+      github:
+        org: platform
+        baseURL: https://github.example.com/api/v3/
+        token: ghp_...
+      local:
+        dir: /opt/educatesenv/ghe/bin
+
+The settings a profile sets replace the top-level ones while it is in use, and its
+development builds are added to the top-level ones. The top-level settings are the
+default profile. The current profile is used unless --profile or EDUCATESENV_PROFILE
+selects another.`,
+	SilenceErrors: true,
+	SilenceUsage:  true,
+}
+
+var profileUseCmd = &cobra.Command{
+	Use:               "use <name>",
+	Short:             "Set the current profile",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeProfiles,
+	SilenceErrors:     true,
+	SilenceUsage:      true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := args[0]
+		next, err := loadProfile(name)
+		if err != nil {
+			return err
+		}
+
+		configPath := config.ConfigFile()
+		if name == config.DefaultProfile {
+			_, err = config.UnsetValue(configPath, []string{"currentProfile"})
+		} else {
+			err = config.SetValue(configPath, []string{"currentProfile"}, name)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to set the current profile: %w", err)
+		}
+		fmt.Printf("Switched to profile %s\n", name)
+
+		if env := os.Getenv(config.ProfileEnv); env != "" && env != name {
+			fmt.Fprintf(os.Stderr, "Warning: %s is set to %s, which overrides the current profile\n", config.ProfileEnv, env)
+		}
+		if next.Local.Dir != cfg.Local.Dir {
+			fmt.Printf("Profile %s uses the bin directory %s. Evaluate `educatesenv env` again to put it on your PATH\n", name, next.Local.Dir)
+		}
+		return nil
+	},
+}
+
+var profileListCmd = &cobra.Command{
+	Use:           "list",
+	Short:         "List the profiles",
+	Args:          cobra.NoArgs,
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		names := append([]string{config.DefaultProfile}, cfg.ProfileNames()...)
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "  PROFILE\tREPOSITORY\tBIN DIR")
+		for _, name := range names {
+			c, err := loadProfile(name)
+			if err != nil {
+				return err
+			}
+			marker := " "
+			if name == profileName(cfg.Profile) {
+				marker = "*"
+			}
+			repository := c.Github.Org + "/" + c.Github.Repository
+			if c.Github.BaseURL != "" {
+				repository += " (" + c.Github.BaseURL + ")"
+			}
+			fmt.Fprintf(w, "%s %s\t%s\t%s\n", marker, name, repository, c.Local.Dir)
+		}
+		return w.Flush()
+	},
+}
+
+var profileCurrentCmd = &cobra.Command{
+	Use:           "current",
+	Short:         "Print the name of the profile in use",
+	Args:          cobra.NoArgs,
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		fmt.Println(profileName(cfg.Profile))
+		return nil
+	},
+}
+
+// profileName returns the name shown for a profile in use, which is default for the
+// top-level settings
+func profileName(name string) string {
+	if name == "" {
+		return config.DefaultProfile
+	}
+	return name
+}
+
+// loadProfile loads the configuration as it is with the named profile in use
+func loadProfile(name string) (*config.Config, error) {
+	c := config.New()
+	c.Profile = name
+	if err := c.Load(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// completeProfiles completes the names of the profiles
+func completeProfiles(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) != 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return append([]string{config.DefaultProfile}, cfg.ProfileNames()...), cobra.ShellCompDirectiveNoFileComp
+}
+
+func init() {
+	profileCmd.AddCommand(profileUseCmd, profileListCmd, profileCurrentCmd)
+	rootCmd.AddCommand(profileCmd)
+}
@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/educates/educatesenv/pkg/version"
+)
+
+var (
+	pruneKeep        int
+	pruneOlderThan   string
+	pruneUnusedSince string
+	pruneDryRun      bool
+)
+
+var pruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Remove installed versions according to a retention policy",
+	Long: `Remove installed versions according to a retention policy.
+
+When several policies are given, a version must match all of them to be removed.
+The active version and the development version are never removed.
+
+Examples:
+  # Keep only the three newest versions
+  educatesenv prune --keep 3
+  # Remove versions installed more than 90 days ago that were not used in the last 30 days
+  educatesenv prune --older-than 90d --unused-since 30d --dry-run`,
+	Args:          cobra.NoArgs,
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		policy := version.PrunePolicy{Keep: pruneKeep}
+		var err error
+		if pruneOlderThan != "" {
+			if policy.OlderThan, err = version.ParseAge(pruneOlderThan); err != nil {
+				return fmt.Errorf("invalid --older-than: %w", err)
+			}
+		}
+		if pruneUnusedSince != "" {
+			if policy.UnusedSince, err = version.ParseAge(pruneUnusedSince); err != nil {
+				return fmt.Errorf("invalid --unused-since: %w", err)
+			}
+		}
+
+		removed, err := manager.Prune(policy, pruneDryRun)
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case len(removed) == 0:
+			fmt.Println("Nothing to prune")
+		case pruneDryRun:
+			fmt.Printf("Would remove: %s\n", strings.Join(removed, ", "))
+		default:
+			fmt.Printf("Removed: %s\n", strings.Join(removed, ", "))
+		}
+		return nil
+	},
+}
+
+func init() {
+	pruneCmd.Flags().IntVar(&pruneKeep, "keep", 0, "Keep the N newest versions by semantic version")
+	pruneCmd.Flags().StringVar(&pruneOlderThan, "older-than", "", "Remove versions installed longer ago than this, such as 90d")
+	pruneCmd.Flags().StringVar(&pruneUnusedSince, "unused-since", "", "Remove versions not used within this period, such as 30d")
+	pruneCmd.Flags().BoolVar(&pruneDryRun, "dry-run", false, "Show what would be removed without removing anything")
+	rootCmd.AddCommand(pruneCmd)
+}
@@ -2,11 +2,14 @@ package cmd
 
 import (
 	"fmt"
+	"os"
 
 	"github.com/spf13/cobra"
 
 	"github.com/educates/educatesenv/pkg/config"
 	"github.com/educates/educatesenv/pkg/github"
+	"github.com/educates/educatesenv/pkg/paths"
+	"github.com/educates/educatesenv/pkg/plugin"
 	"github.com/educates/educatesenv/pkg/version"
 )
 
@@ -14,6 +17,8 @@ var (
 	cfg     *config.Config
 	gh      *github.Client
 	manager *version.Manager
+	noHooks bool
+	profile string
 )
 
 var rootCmd = &cobra.Command{
@@ -21,38 +26,97 @@ var rootCmd = &cobra.Command{
 	Short: "Manage multiple versions of the educates binary",
 	Long:  `A version manager for educates, inspired by tfenv.`,
 	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
-		// Skip validation for help command
-		if cmd.Name() == "help" {
+		// Skip validation for help and shell completion requests, whose output must not
+		// contain anything but the completion results
+		if cmd.Name() == "help" || cmd.Name() == cobra.ShellCompRequestCmd || cmd.Name() == cobra.ShellCompNoDescRequestCmd {
 			return nil
 		}
 
 		// Validate development mode configuration
 		if err := manager.ValidateDevelopmentMode(); err != nil {
-			fmt.Printf("Warning: %v\n", err)
+			// Not on stdout, which shells evaluate for commands such as env
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
 		}
 		return nil
 	},
+	PersistentPostRun: func(cmd *cobra.Command, args []string) {
+		notifyUpdate(cmd)
+	},
+}
+
+// ExitCodeError reports that a child process exited with a non-zero status that
+// educatesenv should exit with as well, without printing an error of its own
+type ExitCodeError struct {
+	Code int
+}
+
+func (e *ExitCodeError) Error() string {
+	return fmt.Sprintf("exit status %d", e.Code)
 }
 
-// Execute executes the root command
+// Execute executes the root command, or the plugin providing the subcommand if it is not
+// built in
 func Execute() error {
+	if name, args, ok := pluginInvocation(os.Args[1:]); ok {
+		if p, found := plugin.Find(name, plugin.SearchPath(paths.Resolve().PluginsDir())); found {
+			initDependencies()
+			return runPlugin(p, args)
+		}
+	}
 	return rootCmd.Execute()
 }
 
 func init() {
 	cobra.OnInitialize(initDependencies)
+	rootCmd.PersistentFlags().BoolVar(&noHooks, "no-hooks", false, "Do not run the hooks configured in the config file")
+	rootCmd.PersistentFlags().StringVar(&profile, "profile", "", "Configuration profile to use instead of the current one (env: "+config.ProfileEnv+")")
+	_ = rootCmd.RegisterFlagCompletionFunc("profile", completeProfiles)
 }
 
 func initDependencies() {
+	// Move files left by versions of educatesenv that kept everything in ~/.educatesenv
+	moved, err := config.MigrateLegacy(paths.Resolve(), paths.LegacyDir())
+	for _, m := range moved {
+		fmt.Fprintf(os.Stderr, "Migrating to the XDG directory layout: %s\n", m)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to migrate %s: %v\n", paths.LegacyDir(), err)
+	}
+
 	// Initialize configuration
+	loadConfig()
+
+	// Upgrade a config file written by an earlier version of educatesenv, keeping a backup
+	configPath := config.ConfigFile()
+	backup, err := config.Migrate(configPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	} else if backup != "" {
+		fmt.Fprintf(os.Stderr, "Upgraded %s to apiVersion %s. The original is in %s\n", configPath, config.CurrentAPIVersion, backup)
+		loadConfig()
+	}
+	for _, w := range cfg.Warnings() {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", w)
+	}
+
+	initManager()
+}
+
+// loadConfig loads the configuration, with the profile selected by --profile
+func loadConfig() {
 	cfg = config.New()
+	cfg.Profile = profile
 	if err := cfg.Load(); err != nil {
 		cobra.CheckErr(err)
 	}
+}
 
+// initManager creates the GitHub client and version manager from the configuration
+func initManager() {
 	// Initialize GitHub client
 	gh = github.New(cfg)
 
 	// Initialize version manager
 	manager = version.New(cfg, gh)
+	manager.SetHooksEnabled(!noHooks)
 }
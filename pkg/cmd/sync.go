@@ -0,0 +1,159 @@
+package cmd
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/educates/educatesenv/pkg/config"
+	"github.com/educates/educatesenv/pkg/manifest"
+	"github.com/educates/educatesenv/pkg/platform"
+	"github.com/educates/educatesenv/pkg/version"
+)
+
+var (
+	syncFile  string
+	syncCheck bool
+	syncPrune bool
+	syncJobs  int
+)
+
+var syncCmd = &cobra.Command{
+	Use:   "sync -f <manifest>",
+	Short: "Bring installed versions in line with a team manifest",
+	Long: `Bring installed versions in line with a team manifest, which lists the versions that
+must be installed, the default version and optionally the repository to install from:
+
+  versions:
+    - 3.1.4
+    - 3.2.3
+  default: 3.2.3
+  source:
+    org: educates
+    repository: educates-training-platform
+
+sync configures the source, installs missing versions and makes the default version
+active. With --prune, installed versions the manifest does not list are removed, except
+for held versions. With --check, differences are reported and sync exits with status 1
+if there are any, without changing anything.`,
+	Args:          cobra.NoArgs,
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		m, err := manifest.Load(syncFile)
+		if err != nil {
+			return err
+		}
+
+		installed, err := manager.InstalledVersions()
+		if err != nil {
+			return err
+		}
+		active, err := manager.ActiveVersion()
+		if err != nil {
+			return fmt.Errorf("failed to determine active version: %w", err)
+		}
+		drift := manifest.Compare(m, manifest.State{
+			Installed: installed,
+			Active:    active,
+			Source:    manifest.Source{Org: cfg.Github.Org, Repository: cfg.Github.Repository},
+		})
+
+		if drift.InSync(syncPrune) {
+			fmt.Printf("In sync with %s\n", syncFile)
+			return nil
+		}
+		printDrift(drift, active)
+		if syncCheck {
+			return &ExitCodeError{Code: 1}
+		}
+
+		if drift.Source != nil {
+			if err := setSource(*drift.Source); err != nil {
+				return err
+			}
+		}
+		if len(drift.Missing) > 0 {
+			if !platform.IsSupportedPlatform(runtime.GOOS, runtime.GOARCH) {
+				return fmt.Errorf("unsupported platform: %s/%s", runtime.GOOS, runtime.GOARCH)
+			}
+			if err := installMany(drift.Missing, syncJobs, nil); err != nil {
+				return err
+			}
+		}
+		if drift.Default != "" {
+			if err := manager.UseVersion(drift.Default); err != nil {
+				return fmt.Errorf("failed to set default version %s: %w", drift.Default, err)
+			}
+			fmt.Printf("Now using educates version %s\n", drift.Default)
+		}
+		if syncPrune && len(drift.Unlisted) > 0 {
+			removed, err := manager.PruneExcept(m.Required(), false)
+			if err != nil {
+				return err
+			}
+			if len(removed) > 0 {
+				fmt.Printf("Removed: %s\n", strings.Join(removed, ", "))
+			}
+			if kept := len(drift.Unlisted) - len(removed); kept > 0 {
+				fmt.Printf("Kept %d unlisted version(s) that are held or active\n", kept)
+			}
+		}
+		return nil
+	},
+}
+
+// printDrift reports how the machine differs from the manifest
+func printDrift(drift manifest.Drift, active string) {
+	if drift.Source != nil {
+		fmt.Printf("Source: %s (configured: %s/%s)\n", drift.Source, cfg.Github.Org, cfg.Github.Repository)
+	}
+	if len(drift.Missing) > 0 {
+		fmt.Printf("Missing versions: %s\n", strings.Join(drift.Missing, ", "))
+	}
+	if drift.Default != "" {
+		if active == "" {
+			active = "none"
+		}
+		fmt.Printf("Default version: %s (active: %s)\n", drift.Default, active)
+	}
+	if len(drift.Unlisted) > 0 {
+		note := ""
+		if !syncPrune {
+			note = " (use --prune to remove)"
+		}
+		fmt.Printf("Unlisted versions: %s%s\n", strings.Join(drift.Unlisted, ", "), note)
+	}
+}
+
+// setSource configures the repository releases are installed from
+func setSource(source manifest.Source) error {
+	for _, key := range []string{"github.org", "github.repository"} {
+		if err := cfg.CheckUnlocked(key); err != nil {
+			return fmt.Errorf("failed to set the source: %w", err)
+		}
+	}
+	configPath := config.ConfigFile()
+	if err := config.SetValue(configPath, cfg.SettingPath("github", "org"), source.Org); err != nil {
+		return fmt.Errorf("failed to set github.org: %w", err)
+	}
+	if err := config.SetValue(configPath, cfg.SettingPath("github", "repository"), source.Repository); err != nil {
+		return fmt.Errorf("failed to set github.repository: %w", err)
+	}
+	cfg.Github.Org = source.Org
+	cfg.Github.Repository = source.Repository
+	initManager()
+	fmt.Printf("Set source to %s in %s\n", source, configPath)
+	return nil
+}
+
+func init() {
+	syncCmd.Flags().StringVarP(&syncFile, "file", "f", "", "Manifest listing the versions to install")
+	syncCmd.Flags().BoolVar(&syncCheck, "check", false, "Report differences and exit with status 1 if there are any, without changing anything")
+	syncCmd.Flags().BoolVar(&syncPrune, "prune", false, "Remove installed versions the manifest does not list")
+	syncCmd.Flags().IntVarP(&syncJobs, "jobs", "j", version.DefaultInstallWorkers, "Number of versions to download at once")
+	_ = syncCmd.MarkFlagRequired("file")
+	rootCmd.AddCommand(syncCmd)
+}
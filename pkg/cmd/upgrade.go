@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"fmt"
+	"runtime"
+
+	"github.com/spf13/cobra"
+
+	"github.com/educates/educatesenv/pkg/platform"
+	"github.com/educates/educatesenv/pkg/version"
+)
+
+var (
+	upgradePatch bool
+	upgradeMinor bool
+	upgradeMajor bool
+)
+
+var upgradeCmd = &cobra.Command{
+	Use:   "upgrade [version]",
+	Short: "Install the newest release in the stream of a version and switch to it",
+	Long: `Install the newest release newer than the active version, or the given installed
+version, and switch to it if the version being upgraded was active. The old version
+stays installed; use prune to remove it.
+
+By default only patch releases are considered (same minor version). Use --minor to
+allow a new minor version, or --major to allow any newer release. Held versions are
+not upgraded.`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeInstalledVersions,
+	SilenceErrors:     true,
+	SilenceUsage:      true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !platform.IsSupportedPlatform(runtime.GOOS, runtime.GOARCH) {
+			return fmt.Errorf("unsupported platform: %s/%s", runtime.GOOS, runtime.GOARCH)
+		}
+
+		activeVersion, err := manager.ActiveVersion()
+		if err != nil {
+			return fmt.Errorf("failed to determine active version: %w", err)
+		}
+		current := activeVersion
+		if len(args) == 1 {
+			resolved, installed, err := manager.ResolveInstalled(args[0])
+			if err != nil {
+				return err
+			}
+			if !installed {
+				return fmt.Errorf("version %s is not installed", args[0])
+			}
+			current = resolved
+		}
+		if current == "" {
+			return fmt.Errorf("no version is active. Pass the version to upgrade")
+		}
+		if version.IsDevVersion(current) {
+			return fmt.Errorf("%s is a development build, which cannot be upgraded", current)
+		}
+
+		held, err := manager.IsHeld(current)
+		if err != nil {
+			return err
+		}
+		if held {
+			return fmt.Errorf("version %s is held. Run `educatesenv unhold %s` to upgrade it", current, current)
+		}
+
+		level := version.UpgradePatch
+		switch {
+		case upgradeMajor:
+			level = version.UpgradeMajor
+		case upgradeMinor:
+			level = version.UpgradeMinor
+		}
+
+		tags, err := releaseTags()
+		if err != nil {
+			return err
+		}
+		upgrades, err := version.AvailableUpgrades(current, tags)
+		if err != nil {
+			return fmt.Errorf("cannot upgrade %s: %w", current, err)
+		}
+		target, err := upgrades.Level(level)
+		if err != nil {
+			return err
+		}
+		if target == "" {
+			fmt.Printf("educates %s is the newest %s release\n", current, level)
+			if upgrades.Major != "" {
+				fmt.Printf("Newer releases are available; run `educatesenv outdated` to see them\n")
+			}
+			return nil
+		}
+
+		if err := manager.InstallVersion(target, false, current == activeVersion); err != nil {
+			return fmt.Errorf("failed to upgrade %s to %s: %w", current, target, err)
+		}
+		fmt.Printf("Upgraded %s to %s\n", current, target)
+		return nil
+	},
+}
+
+func init() {
+	upgradeCmd.Flags().BoolVar(&upgradePatch, "patch", false, "Upgrade to the newest patch release (default)")
+	upgradeCmd.Flags().BoolVar(&upgradeMinor, "minor", false, "Upgrade to the newest minor release")
+	upgradeCmd.Flags().BoolVar(&upgradeMajor, "major", false, "Upgrade to the newest release")
+	upgradeCmd.MarkFlagsMutuallyExclusive("patch", "minor", "major")
+	rootCmd.AddCommand(upgradeCmd)
+}
@@ -2,51 +2,58 @@ package cmd
 
 import (
 	"fmt"
-	"os"
 
 	"github.com/spf13/cobra"
+
+	"github.com/educates/educatesenv/pkg/version"
 )
 
 var useCmd = &cobra.Command{
-	Use:           "use [version|develop]",
-	Short:         "Switch to a specific educates version",
-	Args:          cobra.ExactArgs(1),
-	SilenceErrors: true,
-	SilenceUsage:  true,
+	Use:               "use [version|alias|develop|dev:<name>]",
+	Short:             "Switch to a specific educates version",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeInstalledVersions,
+	SilenceErrors:     true,
+	SilenceUsage:      true,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		version := args[0]
-
-		// Handle development version
-		if version == "develop" {
-			if !cfg.Development.Enabled {
-				return fmt.Errorf("development mode is not enabled. Enable it in the config file by setting development.enabled to true")
-			}
-			if cfg.Development.BinaryLocation == "" {
-				return fmt.Errorf("development binary location is not set. Set development.binaryLocation in the config file")
-			}
+		spec := args[0]
+		resolved, installed, err := manager.ResolveInstalled(spec)
+		if err != nil {
+			return fmt.Errorf("%s is not a version, constraint or alias: %w", spec, err)
+		}
 
-			// Check if the development binary exists
-			if _, err := os.Stat(cfg.Development.BinaryLocation); err != nil {
-				if os.IsNotExist(err) {
-					return fmt.Errorf("development binary not found at %s", cfg.Development.BinaryLocation)
-				}
-				return fmt.Errorf("failed to check development binary: %w", err)
+		// Handle development builds
+		if version.IsDevVersion(resolved) {
+			binary, err := manager.Binary(resolved)
+			if err != nil {
+				return err
 			}
 
-			if err := manager.UseVersion(version); err != nil {
+			if err := manager.UseVersion(resolved); err != nil {
 				return fmt.Errorf("failed to switch to development version: %w", err)
 			}
 
-			fmt.Printf("Now using educates development version from %s\n", cfg.Development.BinaryLocation)
+			if resolved == version.DevVersion {
+				fmt.Printf("Now using educates development version from %s\n", binary)
+			} else {
+				fmt.Printf("Now using educates development build %s from %s\n", resolved, binary)
+			}
 			return nil
 		}
 
 		// Handle regular version
-		if err := manager.UseVersion(version); err != nil {
-			return fmt.Errorf("version %s is not installed. You should install it first with `educatesenv install %s`", version, version)
+		if !installed {
+			return fmt.Errorf("version %s is not installed. You should install it first with `educatesenv install %s`", spec, spec)
+		}
+		if err := manager.UseVersion(resolved); err != nil {
+			return fmt.Errorf("failed to switch to version %s: %w", resolved, err)
 		}
 
-		fmt.Printf("Now using educates version %s\n", version)
+		if resolved != spec {
+			fmt.Printf("Now using educates version %s (%s)\n", resolved, spec)
+		} else {
+			fmt.Printf("Now using educates version %s\n", resolved)
+		}
 		return nil
 	},
 }
@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/educates/educatesenv/pkg/version"
+)
+
+var verifyCmd = &cobra.Command{
+	Use:   "verify [version...]",
+	Short: "Check installed binaries against the checksums recorded when they were installed",
+	Long: `Re-hash installed educates binaries and compare them with the SHA-256 checksums
+recorded at install time, to detect tampering or corruption. Without arguments, all
+installed and recorded versions are checked.`,
+	ValidArgsFunction: completeInstalledVersions,
+	SilenceErrors:     true,
+	SilenceUsage:      true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		results, err := manager.Verify(args...)
+		if err != nil {
+			return err
+		}
+
+		failed := 0
+		for _, result := range results {
+			switch result.Status {
+			case version.VerifyOK:
+				fmt.Printf("  %s: OK\n", result.Version)
+			case version.VerifyUnrecorded:
+				fmt.Printf("  %s: not recorded (installed before install records were kept), sha256 %s\n", result.Version, result.Actual)
+			case version.VerifyMissing:
+				fmt.Printf("  %s: MISSING, binary not found\n", result.Version)
+				failed++
+			default:
+				fmt.Printf("  %s: MODIFIED, expected sha256 %s, got %s\n", result.Version, result.Expected, result.Actual)
+				failed++
+			}
+		}
+
+		if len(results) == 0 {
+			fmt.Println("No versions installed")
+		}
+		if failed > 0 {
+			return fmt.Errorf("%d of %d versions failed verification; reinstall them with `educatesenv install <version> --overwrite`", failed, len(results))
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(verifyCmd)
+}
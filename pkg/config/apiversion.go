@@ -0,0 +1,166 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// CurrentAPIVersion is the apiVersion of config files written by this version of educatesenv
+const CurrentAPIVersion = "educatesenv/v1"
+
+// migration upgrades a config file from one apiVersion to the next
+type migration struct {
+	// from is the apiVersion upgraded from, which is empty for files written before
+	// apiVersion was introduced
+	from  string
+	to    string
+	apply func(root *yaml.Node)
+}
+
+// migrations upgrade config files written by earlier versions of educatesenv, in order. A
+// change that existing files must be rewritten for, such as moving or renaming a setting,
+// introduces a new apiVersion and a migration to it.
+var migrations = []migration{
+	{from: "", to: "educatesenv/v1", apply: canonicalize},
+}
+
+// APIVersions returns the apiVersions this version of educatesenv can load, oldest first
+func APIVersions() []string {
+	var versions []string
+	for _, m := range migrations {
+		if m.from != "" {
+			versions = append(versions, m.from)
+		}
+	}
+	return append(versions, CurrentAPIVersion)
+}
+
+// Migrate upgrades the config file at path to CurrentAPIVersion if it was written for an
+// earlier one, keeping the original next to it. It returns the path of the backup, which is
+// empty if the file is missing or already current. Comments in the file are preserved.
+func Migrate(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to read config file: %w", err)
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return "", fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+	if doc.Kind == 0 || len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return "", nil
+	}
+	if apiVersion, _ := apiVersionOf(doc.Content[0]); apiVersion == CurrentAPIVersion {
+		return "", nil
+	}
+	if err := upgrade(doc.Content[0]); err != nil {
+		return "", fmt.Errorf("failed to upgrade config file %s: %w", path, err)
+	}
+
+	backup := fmt.Sprintf("%s.%s.bak", path, time.Now().Format("20060102-150405"))
+	if err := os.WriteFile(backup, data, 0o600); err != nil {
+		return "", fmt.Errorf("failed to back up config file to %s: %w", backup, err)
+	}
+	if err := writeDocument(path, &doc); err != nil {
+		return "", err
+	}
+	return backup, nil
+}
+
+// upgrade applies the migrations from the apiVersion of a config document to
+// CurrentAPIVersion, and sets its apiVersion
+func upgrade(root *yaml.Node) error {
+	apiVersion, _ := apiVersionOf(root)
+	if apiVersion == CurrentAPIVersion {
+		return nil
+	}
+	start := -1
+	for i, m := range migrations {
+		if m.from == apiVersion {
+			start = i
+			break
+		}
+	}
+	if start < 0 {
+		return fmt.Errorf("%q is not supported by this version of educatesenv, which supports %s", apiVersion, strings.Join(APIVersions(), ", "))
+	}
+	for _, m := range migrations[start:] {
+		m.apply(root)
+	}
+	setAPIVersion(root, CurrentAPIVersion)
+	return nil
+}
+
+// apiVersionOf returns the apiVersion of a config document, and the node holding it
+func apiVersionOf(root *yaml.Node) (string, *yaml.Node) {
+	node := lookupKey(root, "apiVersion")
+	if node == nil || node.Kind != yaml.ScalarNode {
+		return "", node
+	}
+	return node.Value, node
+}
+
+// setAPIVersion sets the apiVersion of a config document, adding it as the first key if
+// it is missing
+func setAPIVersion(root *yaml.Node, apiVersion string) {
+	if node := lookupKey(root, "apiVersion"); node != nil {
+		node.Kind, node.Tag, node.Value, node.Style = yaml.ScalarNode, "!!str", apiVersion, 0
+		return
+	}
+	root.Content = append([]*yaml.Node{
+		{Kind: yaml.ScalarNode, Tag: "!!str", Value: "apiVersion"},
+		{Kind: yaml.ScalarNode, Tag: "!!str", Value: apiVersion},
+	}, root.Content...)
+}
+
+// canonicalize upgrades a config file written before apiVersion was introduced. Such files
+// were read ignoring the case of keys, and accepted a string where a list was expected, so
+// keys are rewritten in their documented case, and a string given for a list becomes a
+// list of that string.
+func canonicalize(root *yaml.Node) {
+	canonicalizeNode(root, reflect.TypeOf(Config{}))
+}
+
+// canonicalizeNode canonicalizes node, which holds a value of type t
+func canonicalizeNode(node *yaml.Node, t reflect.Type) {
+	switch t.Kind() {
+	case reflect.Struct:
+		if node.Kind != yaml.MappingNode {
+			return
+		}
+		fields := yamlFields(t)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			k := node.Content[i]
+			field, ok := fields[k.Value]
+			if !ok {
+				for name, f := range fields {
+					if strings.EqualFold(name, k.Value) && lookupKey(node, name) == nil {
+						k.Value, field, ok = name, f, true
+						break
+					}
+				}
+			}
+			if ok {
+				canonicalizeNode(node.Content[i+1], field.Type)
+			}
+		}
+	case reflect.Slice:
+		if node.Kind == yaml.ScalarNode && node.Tag == "!!str" {
+			item := *node
+			item.HeadComment, item.LineComment, item.FootComment = "", "", ""
+			*node = yaml.Node{
+				Kind: yaml.SequenceNode, Tag: "!!seq", Line: node.Line, Column: node.Column,
+				HeadComment: node.HeadComment, LineComment: node.LineComment, FootComment: node.FootComment,
+				Content: []*yaml.Node{&item},
+			}
+		}
+	}
+}
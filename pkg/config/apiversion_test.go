@@ -0,0 +1,72 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMigrate(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	original := []byte(`# educatesenv configuration
+GitHub:
+  Org: educates # the upstream organization
+development:
+  binarylocation: /src/educates
+hooks:
+  postUse: educates completion bash > ~/.educates-completion
+`)
+	assert.NoError(t, os.WriteFile(path, original, 0o644))
+
+	backup, err := Migrate(path)
+	assert.NoError(t, err)
+	assert.Equal(t, dir, filepath.Dir(backup))
+	assert.Regexp(t, `^config\.yaml\.\d{8}-\d{6}\.bak$`, filepath.Base(backup))
+	data, err := os.ReadFile(backup)
+	assert.NoError(t, err)
+	assert.Equal(t, original, data)
+
+	data, err = os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, `apiVersion: educatesenv/v1
+# educatesenv configuration
+github:
+    org: educates # the upstream organization
+development:
+    binaryLocation: /src/educates
+hooks:
+    postUse:
+        - educates completion bash > ~/.educates-completion
+`, string(data))
+	apiVersion, err := ValidateFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, CurrentAPIVersion, apiVersion)
+
+	// A current file is left alone
+	backup, err = Migrate(path)
+	assert.NoError(t, err)
+	assert.Empty(t, backup)
+
+	backup, err = Migrate(filepath.Join(dir, "missing.yaml"))
+	assert.NoError(t, err)
+	assert.Empty(t, backup)
+}
+
+func TestMigrateUnsupported(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	assert.NoError(t, os.WriteFile(path, []byte("apiVersion: educatesenv/v9\n"), 0o644))
+
+	_, err := Migrate(path)
+	assert.ErrorContains(t, err, "not supported")
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "apiVersion: educatesenv/v9\n", string(data))
+}
+
+func TestAPIVersions(t *testing.T) {
+	assert.Equal(t, []string{CurrentAPIVersion}, APIVersions())
+	assert.Equal(t, CurrentAPIVersion, migrations[len(migrations)-1].to)
+}
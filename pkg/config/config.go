@@ -2,11 +2,13 @@ package config
 
 import (
 	"fmt"
+	"net/url"
 	"os"
-	"path/filepath"
 
 	"github.com/spf13/viper"
 	"gopkg.in/yaml.v3"
+
+	"github.com/educates/educatesenv/pkg/paths"
 )
 
 const (
@@ -14,8 +16,8 @@ const (
 	DefaultGithubOrg = "educates"
 	// DefaultGithubRepo is the default GitHub repository for educates
 	DefaultGithubRepo = "educates-training-platform"
-	// ConfigDirName is the name of the directory where educatesenv stores its configuration
-	ConfigDirName = ".educatesenv"
+	// DefaultUpdateCheckInterval is how often the update notifier checks for a new release by default
+	DefaultUpdateCheckInterval = "24h"
 )
 
 // GithubConfig holds GitHub-related configuration
@@ -23,6 +25,9 @@ type GithubConfig struct {
 	Org        string `yaml:"org"`
 	Repository string `yaml:"repository"`
 	Token      string `yaml:"token"`
+	// BaseURL is the API URL of a GitHub Enterprise Server, such as https://github.example.com/api/v3/.
+	// When empty, github.com is used.
+	BaseURL string `yaml:"baseURL"`
 }
 
 // LocalConfig holds local directory configuration
@@ -34,30 +39,88 @@ type LocalConfig struct {
 type DevelopmentConfig struct {
 	Enabled        bool   `yaml:"enabled"`
 	BinaryLocation string `yaml:"binaryLocation"`
+	// Builds maps the names of additional development builds, used as dev:<name>, to their binaries
+	Builds map[string]string `yaml:"builds"`
+	// BuildCommand builds the educates client from a checkout for `educatesenv dev build`.
+	// When empty, the client is built with go build from the client-programs directory.
+	BuildCommand string `yaml:"buildCommand"`
+}
+
+// VerifyConfig holds release signature verification configuration
+type VerifyConfig struct {
+	Required   bool     `yaml:"required"`
+	PublicKeys []string `yaml:"publicKeys"`
+}
+
+// AutoPruneConfig holds the retention policy applied after successful installs
+type AutoPruneConfig struct {
+	Enabled     bool   `yaml:"enabled"`
+	Keep        int    `yaml:"keep"`
+	OlderThan   string `yaml:"olderThan"`
+	UnusedSince string `yaml:"unusedSince"`
+}
+
+// HooksConfig holds shell commands run before and after installs and version switches
+type HooksConfig struct {
+	PreInstall  []string `yaml:"preInstall"`
+	PostInstall []string `yaml:"postInstall"`
+	PreUse      []string `yaml:"preUse"`
+	PostUse     []string `yaml:"postUse"`
+}
+
+// UpdateNotifierConfig holds configuration of the notice shown when a newer educates release exists
+type UpdateNotifierConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// Interval is the minimum time between checks for a new release, such as 24h or 7d
+	Interval string `yaml:"interval"`
 }
 
 // Config holds all configuration for the CLI
 type Config struct {
-	Github      GithubConfig      `yaml:"github"`
-	Local       LocalConfig       `yaml:"local"`
-	Development DevelopmentConfig `yaml:"development"`
+	// APIVersion is the version of the config file format
+	APIVersion string `yaml:"apiVersion"`
+	// CurrentProfile is the profile used unless another is selected with --profile or
+	// EDUCATESENV_PROFILE. When empty, the top-level settings are used.
+	CurrentProfile string               `yaml:"currentProfile"`
+	Github         GithubConfig         `yaml:"github"`
+	Local          LocalConfig          `yaml:"local"`
+	Development    DevelopmentConfig    `yaml:"development"`
+	Verify         VerifyConfig         `yaml:"verify"`
+	AutoPrune      AutoPruneConfig      `yaml:"autoPrune"`
+	Hooks          HooksConfig          `yaml:"hooks"`
+	UpdateNotifier UpdateNotifierConfig `yaml:"updateNotifier"`
+	// Profiles are named sets of GitHub, local and development settings, which replace the
+	// top-level ones while the profile is in use
+	Profiles map[string]ProfileConfig `yaml:"profiles"`
+
+	// Locked lists the settings, or sections of settings, that only the system config file
+	// can set. It can only be set in the system config file.
+	Locked []string `yaml:"locked,omitempty"`
+
+	// Profile is the profile in use, which is empty for the top-level settings. Set it before
+	// Load to select a profile, overriding EDUCATESENV_PROFILE and CurrentProfile.
+	Profile string `yaml:"-"`
+
+	// files are the config files that were loaded, in increasing order of precedence
+	files []File
+	// warnings are problems found while loading that did not stop it
+	warnings []string
 }
 
 // New returns a new Config instance with defaults set
 func New() *Config {
-	home, err := os.UserHomeDir()
-	if err != nil {
-		fmt.Println("Warning: could not determine home directory, using current directory for bin folder.")
-		home = "."
-	}
-	configDir := filepath.Join(home, ConfigDirName)
-	defaultBin := filepath.Join(configDir, "bin")
+	// In portable mode the bin directory is relative to the config file, so that a config
+	// file written with the defaults keeps working when the tree is moved
+	layout := paths.Resolve()
+	defaultBin := layout.Rel(layout.BinDir())
 
 	return &Config{
+		APIVersion: CurrentAPIVersion,
 		Github: GithubConfig{
 			Org:        DefaultGithubOrg,
 			Repository: DefaultGithubRepo,
 			Token:      "",
+			BaseURL:    "",
 		},
 		Local: LocalConfig{
 			Dir: defaultBin,
@@ -65,81 +128,201 @@ func New() *Config {
 		Development: DevelopmentConfig{
 			Enabled:        false,
 			BinaryLocation: "",
+			Builds:         map[string]string{},
+			BuildCommand:   "",
+		},
+		Verify: VerifyConfig{
+			Required:   false,
+			PublicKeys: []string{},
+		},
+		AutoPrune: AutoPruneConfig{
+			Enabled:     false,
+			Keep:        0,
+			OlderThan:   "",
+			UnusedSince: "",
 		},
+		Hooks: HooksConfig{
+			PreInstall:  []string{},
+			PostInstall: []string{},
+			PreUse:      []string{},
+			PostUse:     []string{},
+		},
+		UpdateNotifier: UpdateNotifierConfig{
+			Enabled:  false,
+			Interval: DefaultUpdateCheckInterval,
+		},
+		Profiles: map[string]ProfileConfig{},
 	}
 }
 
-// Load initializes the configuration from file and environment variables
-func (c *Config) Load() error {
-	home, err := os.UserHomeDir()
-	if err != nil {
-		fmt.Println("Warning: could not determine home directory, using current directory for bin folder.")
-		home = "."
+// envBindings are the environment variables that override settings
+var envBindings = []struct{ key, env string }{
+	{"github.org", "EDUCATES_GITHUB_ORG"},
+	{"github.repository", "EDUCATES_GITHUB_REPOSITORY"},
+	{"github.token", "EDUCATES_GITHUB_TOKEN"},
+	{"github.baseURL", "EDUCATES_GITHUB_BASE_URL"},
+	{"local.dir", "EDUCATES_LOCAL_DIR"},
+	{"development.enabled", "EDUCATES_DEVELOPMENT_ENABLED"},
+	{"development.binaryLocation", "EDUCATES_DEVELOPMENT_BINARY_LOCATION"},
+	{"verify.required", "EDUCATES_VERIFY_REQUIRED"},
+}
+
+// defaults returns the default value of each setting
+func defaults(layout paths.Layout) map[string]any {
+	return map[string]any{
+		"github.org":                 DefaultGithubOrg,
+		"github.repository":          DefaultGithubRepo,
+		"github.token":               "",
+		"github.baseURL":             "",
+		"local.dir":                  layout.BinDir(),
+		"development.enabled":        false,
+		"development.binaryLocation": "",
+		"development.builds":         map[string]string{},
+		"development.buildCommand":   "",
+		"verify.required":            false,
+		"verify.publicKeys":          []string{},
+		"autoPrune.enabled":          false,
+		"autoPrune.keep":             0,
+		"autoPrune.olderThan":        "",
+		"autoPrune.unusedSince":      "",
+		"hooks.preInstall":           []string{},
+		"hooks.postInstall":          []string{},
+		"hooks.preUse":               []string{},
+		"hooks.postUse":              []string{},
+		"updateNotifier.enabled":     false,
+		"updateNotifier.interval":    DefaultUpdateCheckInterval,
 	}
-	configDir := filepath.Join(home, ConfigDirName)
-	defaultBin := filepath.Join(configDir, "bin")
+}
 
-	viper.SetConfigName("config")
-	viper.SetConfigType("yaml")
-	viper.AddConfigPath(".")
-	viper.AddConfigPath(configDir)
+// Load initializes the configuration from the config files, the selected profile and
+// environment variables, in increasing order of precedence. The config files are the
+// system config file, the user config file and the nearest project config file, in
+// increasing order of precedence. Settings locked by the system config file are only
+// read from it.
+func (c *Config) Load() error {
+	layout := paths.Resolve()
+	v := viper.New()
 
 	// Set defaults
-	viper.SetDefault("github.org", DefaultGithubOrg)
-	viper.SetDefault("github.repository", DefaultGithubRepo)
-	viper.SetDefault("github.token", "")
-	viper.SetDefault("local.dir", defaultBin)
-	viper.SetDefault("development.enabled", false)
-	viper.SetDefault("development.binaryLocation", "")
+	settingDefaults := defaults(layout)
+	for key, value := range settingDefaults {
+		v.SetDefault(key, value)
+	}
 
 	// Bind environment variables
-	if err := viper.BindEnv("github.org", "EDUCATES_GITHUB_ORG"); err != nil {
-		return fmt.Errorf("failed to bind env EDUCATES_GITHUB_ORG: %w", err)
-	}
-	if err := viper.BindEnv("github.repository", "EDUCATES_GITHUB_REPOSITORY"); err != nil {
-		return fmt.Errorf("failed to bind env EDUCATES_GITHUB_REPOSITORY: %w", err)
-	}
-	if err := viper.BindEnv("github.token", "EDUCATES_GITHUB_TOKEN"); err != nil {
-		return fmt.Errorf("failed to bind env EDUCATES_GITHUB_TOKEN: %w", err)
+	for _, b := range envBindings {
+		if err := v.BindEnv(b.key, b.env); err != nil {
+			return fmt.Errorf("failed to bind env %s: %w", b.env, err)
+		}
 	}
-	if err := viper.BindEnv("local.dir", "EDUCATES_LOCAL_DIR"); err != nil {
-		return fmt.Errorf("failed to bind env EDUCATES_LOCAL_DIR: %w", err)
+
+	// Read the config files that exist, rejecting unknown keys and values of the wrong
+	// type, which would otherwise be ignored
+	c.warnings = nil
+	files, err := c.readFiles(layout)
+	if err != nil {
+		return err
 	}
-	if err := viper.BindEnv("development.enabled", "EDUCATES_DEVELOPMENT_ENABLED"); err != nil {
-		return fmt.Errorf("failed to bind env EDUCATES_DEVELOPMENT_ENABLED: %w", err)
+	c.files = files
+	for _, f := range files {
+		// Viper folds the case of the keys of the map it merges, so it gets a copy
+		if err := v.MergeConfigMap(copyValues(f.values)); err != nil {
+			return fmt.Errorf("failed to read config file %s: %w", f.Path, err)
+		}
 	}
-	if err := viper.BindEnv("development.binaryLocation", "EDUCATES_DEVELOPMENT_BINARY_LOCATION"); err != nil {
-		return fmt.Errorf("failed to bind env EDUCATES_DEVELOPMENT_BINARY_LOCATION: %w", err)
+
+	if err := c.selectProfile(v); err != nil {
+		return err
 	}
+	c.applyLocks(v, settingDefaults)
+
+	// Map the configuration to our struct. Files written for an earlier apiVersion have
+	// been upgraded in memory by readFiles.
+	c.APIVersion = CurrentAPIVersion
+	c.Github.Org = v.GetString("github.org")
+	c.Github.Repository = v.GetString("github.repository")
+	c.Github.Token = v.GetString("github.token")
+	c.Github.BaseURL = v.GetString("github.baseURL")
+	c.Local.Dir = v.GetString("local.dir")
+	c.Development.Enabled = v.GetBool("development.enabled")
+	c.Development.BinaryLocation = v.GetString("development.binaryLocation")
+	c.Development.Builds = v.GetStringMapString("development.builds")
+	c.Development.BuildCommand = v.GetString("development.buildCommand")
+	c.Verify.Required = v.GetBool("verify.required")
+	c.Verify.PublicKeys = v.GetStringSlice("verify.publicKeys")
+	c.AutoPrune.Enabled = v.GetBool("autoPrune.enabled")
+	c.AutoPrune.Keep = v.GetInt("autoPrune.keep")
+	c.AutoPrune.OlderThan = v.GetString("autoPrune.olderThan")
+	c.AutoPrune.UnusedSince = v.GetString("autoPrune.unusedSince")
+	c.Hooks.PreInstall = v.GetStringSlice("hooks.preInstall")
+	c.Hooks.PostInstall = v.GetStringSlice("hooks.postInstall")
+	c.Hooks.PreUse = v.GetStringSlice("hooks.preUse")
+	c.Hooks.PostUse = v.GetStringSlice("hooks.postUse")
+	c.UpdateNotifier.Enabled = v.GetBool("updateNotifier.enabled")
+	c.UpdateNotifier.Interval = v.GetString("updateNotifier.interval")
 
-	// Read config file if present
-	if err := viper.ReadInConfig(); err != nil {
-		if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
-			return fmt.Errorf("error reading config file: %w", err)
+	if c.Github.BaseURL != "" {
+		if u, err := url.Parse(c.Github.BaseURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("github.baseURL %q is not an http or https URL", c.Github.BaseURL)
 		}
 	}
 
-	// Map the configuration to our struct
-	c.Github.Org = viper.GetString("github.org")
-	c.Github.Repository = viper.GetString("github.repository")
-	c.Github.Token = viper.GetString("github.token")
-	c.Local.Dir = viper.GetString("local.dir")
-	c.Development.Enabled = viper.GetBool("development.enabled")
-	c.Development.BinaryLocation = viper.GetString("development.binaryLocation")
+	// In portable mode, relative paths are relative to the config directory
+	c.Local.Dir = layout.Abs(c.Local.Dir)
+	c.Development.BinaryLocation = layout.Abs(c.Development.BinaryLocation)
+	for name, binary := range c.Development.Builds {
+		c.Development.Builds[name] = layout.Abs(binary)
+	}
+
+	return nil
+}
+
+// selectProfile merges the settings of the selected profile over the top-level ones. The
+// profile set in c.Profile takes precedence over EDUCATESENV_PROFILE, which takes
+// precedence over currentProfile in the config files. Environment variables for settings
+// still override the profile.
+func (c *Config) selectProfile(v *viper.Viper) error {
+	c.CurrentProfile = v.GetString("currentProfile")
+	c.Profiles = map[string]ProfileConfig{}
+	if err := v.UnmarshalKey("profiles", &c.Profiles); err != nil {
+		return fmt.Errorf("failed to read profiles: %w", err)
+	}
 
+	name := c.Profile
+	if name == "" {
+		name = os.Getenv(ProfileEnv)
+	}
+	if name == "" {
+		name = c.CurrentProfile
+	}
+	if name == DefaultProfile {
+		name = ""
+	}
+	c.Profile = name
+	if name == "" {
+		return nil
+	}
+	if _, ok := c.Profiles[name]; !ok {
+		return fmt.Errorf("profile %s is not defined in the config files. Use --profile %s to use the top-level settings", name, DefaultProfile)
+	}
+	if err := v.MergeConfigMap(v.GetStringMap("profiles." + name)); err != nil {
+		return fmt.Errorf("failed to apply profile %s: %w", name, err)
+	}
 	return nil
 }
 
+// ConfigFile returns the user config file, which commands that change settings write to
+func ConfigFile() string {
+	return paths.Resolve().ConfigFile()
+}
+
 // CreateConfigAndFolders ensures the config and bin directories exist, and creates a default config.yaml if not present.
 // Returns (configDir, binDir, configPath, configCreated, error)
 func CreateConfigAndFolders() (string, string, string, bool, error) {
-	home, err := os.UserHomeDir()
-	if err != nil {
-		home = "."
-	}
-	configDir := filepath.Join(home, ConfigDirName)
-	binDir := filepath.Join(configDir, "bin")
-	configPath := filepath.Join(configDir, "config.yaml")
+	layout := paths.Resolve()
+	configDir := layout.Config
+	binDir := layout.BinDir()
+	configPath := layout.ConfigFile()
 
 	if err := os.MkdirAll(binDir, 0o755); err != nil {
 		return configDir, binDir, configPath, false, fmt.Errorf("failed to create bin directory: %w", err)
@@ -6,8 +6,23 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/educates/educatesenv/pkg/paths"
 )
 
+// setupConfigDir keeps everything, including the user config file, in a temporary
+// directory, which is also the current directory, and puts the system config file there
+// as system.yaml
+func setupConfigDir(t *testing.T) string {
+	dir := t.TempDir()
+	t.Setenv(paths.RootEnv, dir)
+	t.Chdir(dir)
+	previous := systemConfigFile
+	systemConfigFile = func() string { return filepath.Join(dir, "system.yaml") }
+	t.Cleanup(func() { systemConfigFile = previous })
+	return dir
+}
+
 func TestNew(t *testing.T) {
 	cfg := New()
 	assert.NotNil(t, cfg)
@@ -16,23 +31,21 @@ func TestNew(t *testing.T) {
 	assert.Empty(t, cfg.Github.Token)
 	assert.False(t, cfg.Development.Enabled)
 	assert.Empty(t, cfg.Development.BinaryLocation)
+	assert.False(t, cfg.Verify.Required)
+	assert.Empty(t, cfg.Verify.PublicKeys)
+	assert.False(t, cfg.UpdateNotifier.Enabled)
+	assert.Equal(t, DefaultUpdateCheckInterval, cfg.UpdateNotifier.Interval)
 
-	// Test that Local.Dir is set to a path in the home directory
-	home, err := os.UserHomeDir()
-	if err == nil {
-		expectedPath := filepath.Join(home, ConfigDirName, "bin")
-		assert.Equal(t, expectedPath, cfg.Local.Dir)
-	}
+	// Test that Local.Dir is set to the bin directory of the paths layout
+	assert.Equal(t, paths.Resolve().BinDir(), cfg.Local.Dir)
+
+	t.Setenv(paths.RootEnv, "/opt/educatesenv")
+	assert.Equal(t, filepath.Join("/opt/educatesenv", "bin"), New().Local.Dir)
 }
 
 func TestLoad(t *testing.T) {
 	// Setup temporary directory for test config
-	tmpDir, err := os.MkdirTemp("", "educatesenv-test")
-	assert.NoError(t, err)
-	defer func() {
-		err := os.RemoveAll(tmpDir)
-		assert.NoError(t, err)
-	}()
+	tmpDir := setupConfigDir(t)
 
 	// Create a test config file
 	configContent := []byte(`
@@ -45,20 +58,26 @@ local:
 development:
   enabled: true
   binaryLocation: /test/binary
+  builds:
+    feature-x: /test/feature-x
+verify:
+  required: true
+  publicKeys:
+    - /test/cosign.pub
+autoPrune:
+  enabled: true
+  keep: 3
+  unusedSince: 30d
+hooks:
+  postUse:
+    - educates completion bash > ~/.educates-completion
+updateNotifier:
+  enabled: true
+  interval: 7d
 `)
-	err = os.WriteFile(filepath.Join(tmpDir, "config.yaml"), configContent, 0644)
+	err := os.WriteFile(filepath.Join(tmpDir, "config.yaml"), configContent, 0644)
 	assert.NoError(t, err)
 
-	// Set working directory to temp dir for config loading
-	oldWd, err := os.Getwd()
-	assert.NoError(t, err)
-	err = os.Chdir(tmpDir)
-	assert.NoError(t, err)
-	defer func() {
-		err := os.Chdir(oldWd)
-		assert.NoError(t, err)
-	}()
-
 	// Test loading config from file
 	cfg := New()
 	err = cfg.Load()
@@ -69,9 +88,22 @@ development:
 	assert.Equal(t, "/test/dir", cfg.Local.Dir)
 	assert.True(t, cfg.Development.Enabled)
 	assert.Equal(t, "/test/binary", cfg.Development.BinaryLocation)
+	assert.Equal(t, map[string]string{"feature-x": "/test/feature-x"}, cfg.Development.Builds)
+	assert.True(t, cfg.Verify.Required)
+	assert.Equal(t, []string{"/test/cosign.pub"}, cfg.Verify.PublicKeys)
+	assert.True(t, cfg.AutoPrune.Enabled)
+	assert.Equal(t, 3, cfg.AutoPrune.Keep)
+	assert.Empty(t, cfg.AutoPrune.OlderThan)
+	assert.Equal(t, "30d", cfg.AutoPrune.UnusedSince)
+	assert.Equal(t, []string{"educates completion bash > ~/.educates-completion"}, cfg.Hooks.PostUse)
+	assert.Empty(t, cfg.Hooks.PreUse)
+	assert.True(t, cfg.UpdateNotifier.Enabled)
+	assert.Equal(t, "7d", cfg.UpdateNotifier.Interval)
 }
 
 func TestLoadWithEnvVars(t *testing.T) {
+	setupConfigDir(t)
+
 	// Set environment variables
 	envVars := map[string]string{
 		"EDUCATES_GITHUB_ORG":                  "envorg",
@@ -103,3 +135,14 @@ func TestLoadWithEnvVars(t *testing.T) {
 	assert.True(t, cfg.Development.Enabled)
 	assert.Equal(t, "/env/binary", cfg.Development.BinaryLocation)
 }
+
+func TestLoadInvalid(t *testing.T) {
+	tmpDir := setupConfigDir(t)
+	err := os.WriteFile(filepath.Join(tmpDir, "config.yaml"), []byte("developmet:\n  enabled: true\n"), 0o644)
+	assert.NoError(t, err)
+
+	err = New().Load()
+	var invalid *ValidationError
+	assert.ErrorAs(t, err, &invalid)
+	assert.Equal(t, "developmet", invalid.Problems[0].Key)
+}
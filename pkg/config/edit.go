@@ -0,0 +1,152 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SetValue sets the key at keys, such as development.builds.main, in the config file at
+// path to value. The rest of the file, including comments, is preserved, missing parent
+// keys are created, and the file is created if it does not exist.
+func SetValue(path string, keys []string, value any) error {
+	doc, err := readDocument(path)
+	if err != nil {
+		return err
+	}
+
+	var valueNode yaml.Node
+	if err := valueNode.Encode(value); err != nil {
+		return fmt.Errorf("failed to encode %s: %w", joinKeys(keys), err)
+	}
+
+	node := doc.Content[0]
+	for i, key := range keys {
+		if node.Kind != yaml.MappingNode {
+			return fmt.Errorf("failed to set %s: %s is not a mapping", joinKeys(keys), joinKeys(keys[:i]))
+		}
+		child := lookupKey(node, key)
+		if i == len(keys)-1 {
+			if child != nil {
+				// Keep comments attached to the replaced value
+				valueNode.HeadComment, valueNode.LineComment, valueNode.FootComment = child.HeadComment, child.LineComment, child.FootComment
+				*child = valueNode
+			} else {
+				node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, &valueNode)
+			}
+			break
+		}
+		if child == nil {
+			child = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+			node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, child)
+		}
+		node = child
+	}
+	return writeDocument(path, doc)
+}
+
+// UnsetValue removes the key at keys from the config file at path. It reports whether the
+// key was present.
+func UnsetValue(path string, keys []string) (bool, error) {
+	doc, err := readDocument(path)
+	if err != nil {
+		return false, err
+	}
+
+	node := doc.Content[0]
+	for _, key := range keys[:len(keys)-1] {
+		if node = lookupKey(node, key); node == nil {
+			return false, nil
+		}
+	}
+	if node.Kind != yaml.MappingNode {
+		return false, nil
+	}
+	last := keys[len(keys)-1]
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == last {
+			node.Content = append(node.Content[:i], node.Content[i+2:]...)
+			return true, writeDocument(path, doc)
+		}
+	}
+	return false, nil
+}
+
+// lookupKey returns the value of key in a mapping node, or nil if it is not present
+func lookupKey(node *yaml.Node, key string) *yaml.Node {
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// readDocument parses the config file at path, returning a document with only the current
+// apiVersion if it does not exist or is empty
+func readDocument(path string) (*yaml.Node, error) {
+	doc := &yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}}
+	setAPIVersion(doc.Content[0], CurrentAPIVersion)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return doc, nil
+		}
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var parsed yaml.Node
+	if err := yaml.Unmarshal(data, &parsed); err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+	if parsed.Kind == 0 || len(parsed.Content) == 0 {
+		return doc, nil
+	}
+	if parsed.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("config file %s is not a mapping", path)
+	}
+	return &parsed, nil
+}
+
+// writeDocument writes a config document to path atomically, keeping the mode of an
+// existing file
+func writeDocument(path string, doc *yaml.Node) error {
+	data, err := yaml.Marshal(doc)
+	if err != nil {
+		return fmt.Errorf("failed to encode config file: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	mode, existing := os.FileMode(0o644), false
+	if fi, err := os.Stat(path); err == nil {
+		mode, existing = fi.Mode().Perm(), true
+	}
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, mode); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	// The mode is only applied to a new file, and is masked by the umask
+	if existing {
+		if err := os.Chmod(tmpPath, mode); err != nil {
+			_ = os.Remove(tmpPath)
+			return fmt.Errorf("failed to write config file: %w", err)
+		}
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	return nil
+}
+
+// joinKeys formats keys as a dotted path for messages
+func joinKeys(keys []string) string {
+	return strings.Join(keys, ".")
+}
@@ -0,0 +1,76 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetValue(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	err := os.WriteFile(path, []byte(`# educatesenv configuration
+github:
+    org: educates # upstream
+development:
+    enabled: false
+`), 0o644)
+	assert.NoError(t, err)
+
+	assert.NoError(t, SetValue(path, []string{"development", "enabled"}, true))
+	assert.NoError(t, SetValue(path, []string{"development", "builds", "main"}, "/src/educates/main/bin/educates"))
+	assert.NoError(t, SetValue(path, []string{"development", "builds", "feature-x"}, "/src/educates/feature-x/bin/educates"))
+
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, `# educatesenv configuration
+github:
+    org: educates # upstream
+development:
+    enabled: true
+    builds:
+        main: /src/educates/main/bin/educates
+        feature-x: /src/educates/feature-x/bin/educates
+`, string(data))
+
+	removed, err := UnsetValue(path, []string{"development", "builds", "main"})
+	assert.NoError(t, err)
+	assert.True(t, removed)
+	removed, err = UnsetValue(path, []string{"development", "builds", "main"})
+	assert.NoError(t, err)
+	assert.False(t, removed)
+
+	// A scalar cannot be descended into
+	assert.Error(t, SetValue(path, []string{"github", "org", "name"}, "x"))
+}
+
+func TestSetValueCreatesFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "educatesenv", "config.yaml")
+	assert.NoError(t, SetValue(path, []string{"development", "builds", "main"}, "/src/main"))
+
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "apiVersion: educatesenv/v1\ndevelopment:\n    builds:\n        main: /src/main\n", string(data))
+}
+
+func TestSetValueKeepsMode(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("file modes are not supported on Windows")
+	}
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	assert.NoError(t, os.WriteFile(path, []byte("github:\n    token: secret\n"), 0o600))
+	assert.NoError(t, os.Chmod(path, 0o600))
+
+	assert.NoError(t, SetValue(path, []string{"github", "org"}, "educates"))
+	fi, err := os.Stat(path)
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), fi.Mode().Perm())
+
+	_, err = UnsetValue(path, []string{"github", "org"})
+	assert.NoError(t, err)
+	fi, err = os.Stat(path)
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), fi.Mode().Perm())
+}
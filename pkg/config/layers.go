@@ -0,0 +1,407 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+
+	"github.com/educates/educatesenv/pkg/paths"
+)
+
+// Scopes of config files, in increasing order of precedence
+const (
+	// SystemScope is the system config file, with defaults set by an administrator
+	SystemScope = "system"
+	// UserScope is the user config file, which commands that change settings write to
+	UserScope = "user"
+	// ProjectScope is the nearest .educatesenv.yaml in the current directory or its parents
+	ProjectScope = "project"
+)
+
+// systemConfigFile returns the system config file. It is replaced in tests.
+var systemConfigFile = paths.SystemConfigFile
+
+// projectRestricted are the settings a project config file cannot set, even once trusted.
+// The file comes with a project rather than from the user, so it must not run commands
+// through hooks, send credentials elsewhere or weaken verification, and the profile in
+// use is chosen by the user.
+var projectRestricted = []string{"github.token", "github.baseURL", "development", "verify", "hooks", "profiles", "currentProfile"}
+
+// File is a config file settings are read from
+type File struct {
+	// Scope is SystemScope, UserScope or ProjectScope
+	Scope string
+	// Path is the location of the file
+	Path string
+	// values are the settings in the file, upgraded to CurrentAPIVersion
+	values map[string]any
+}
+
+// Files returns the config files that were loaded, in increasing order of precedence
+func (c *Config) Files() []File {
+	return c.files
+}
+
+// Warnings returns problems found while loading the configuration that did not stop it,
+// such as values of locked settings that were ignored
+func (c *Config) Warnings() []string {
+	return c.warnings
+}
+
+// readFiles reads and validates the config files that exist. A project config file the
+// user does not trust is left out with a warning.
+func (c *Config) readFiles(layout paths.Layout) ([]File, error) {
+	candidates := []File{
+		{Scope: SystemScope, Path: systemConfigFile()},
+		{Scope: UserScope, Path: layout.ConfigFile()},
+	}
+	if wd, err := os.Getwd(); err == nil {
+		if path, found := paths.FindProjectConfig(wd); found {
+			trusted, err := IsTrusted(path)
+			if err != nil {
+				return nil, err
+			}
+			if trusted {
+				candidates = append(candidates, File{Scope: ProjectScope, Path: path})
+			} else {
+				c.warnings = append(c.warnings, fmt.Sprintf("ignoring %s, which is not trusted. Review it and run `educatesenv config trust` to use it", path))
+			}
+		}
+	}
+
+	var files []File
+	for _, f := range candidates {
+		if _, err := os.Stat(f.Path); os.IsNotExist(err) {
+			continue
+		}
+		_, root, err := parseFile(f.Path, f.Scope)
+		if err != nil {
+			return nil, err
+		}
+		f.values = map[string]any{}
+		if root != nil {
+			if err := root.Decode(&f.values); err != nil {
+				return nil, fmt.Errorf("failed to read config file %s: %w", f.Path, err)
+			}
+		}
+		// A relative bin directory in a project file is relative to the project
+		if local, ok := f.values["local"].(map[string]any); ok && f.Scope == ProjectScope {
+			if dir, ok := local["dir"].(string); ok && dir != "" && !filepath.IsAbs(dir) {
+				local["dir"] = filepath.Join(filepath.Dir(f.Path), dir)
+			}
+		}
+		files = append(files, f)
+	}
+	return files, nil
+}
+
+// copyValues returns a deep copy of the settings read from a config file
+func copyValues(values map[string]any) map[string]any {
+	c := make(map[string]any, len(values))
+	for key, value := range values {
+		if m, ok := value.(map[string]any); ok {
+			value = copyValues(m)
+		}
+		c[key] = value
+	}
+	return c
+}
+
+// scopeOf returns the scope of the config file at path
+func scopeOf(path string) string {
+	switch {
+	case path == systemConfigFile():
+		return SystemScope
+	case filepath.Base(path) == paths.ProjectConfigName:
+		return ProjectScope
+	default:
+		return UserScope
+	}
+}
+
+// scopeProblems returns the settings in a config file of the given scope that cannot be
+// set in it, and the locked settings in a system config file that do not exist
+func scopeProblems(root *yaml.Node, scope string) []Problem {
+	var problems []Problem
+	if scope != SystemScope {
+		if k := lookupKeyNode(root, "locked"); k != nil {
+			problems = append(problems, Problem{Line: k.Line, Column: k.Column, Key: "locked", Message: "can only be set in the system config file " + systemConfigFile()})
+		}
+	}
+	if scope == ProjectScope {
+		for _, key := range projectRestricted {
+			if k := lookupKeyNode(root, strings.Split(key, ".")...); k != nil {
+				problems = append(problems, Problem{Line: k.Line, Column: k.Column, Key: key, Message: "cannot be set in a project config file"})
+			}
+		}
+	}
+	if scope == SystemScope {
+		if locked := lookupKey(root, "locked"); locked != nil && locked.Kind == yaml.SequenceNode {
+			settings := Settings()
+			for i, item := range locked.Content {
+				if item.Kind == yaml.ScalarNode && !coversSetting(item.Value, settings) {
+					problems = append(problems, Problem{Line: item.Line, Column: item.Column, Key: fmt.Sprintf("locked[%d]", i), Message: fmt.Sprintf("%s is not a setting that can be locked", item.Value)})
+				}
+			}
+		}
+	}
+	return problems
+}
+
+// lookupKeyNode returns the key node of the setting at keys, or nil if it is not set
+func lookupKeyNode(root *yaml.Node, keys ...string) *yaml.Node {
+	node := root
+	for i, key := range keys {
+		if node == nil || node.Kind != yaml.MappingNode {
+			return nil
+		}
+		var value *yaml.Node
+		for j := 0; j+1 < len(node.Content); j += 2 {
+			if node.Content[j].Value == key {
+				if i == len(keys)-1 {
+					return node.Content[j]
+				}
+				value = node.Content[j+1]
+				break
+			}
+		}
+		node = value
+	}
+	return nil
+}
+
+// Settings returns the keys of the settings that have values, which are the ones that can
+// be locked, in the order they are declared
+func Settings() []string {
+	var keys []string
+	for _, key := range leafSettings(reflect.TypeOf(Config{}), "") {
+		switch strings.SplitN(key, ".", 2)[0] {
+		case "apiVersion", "currentProfile", "profiles", "locked":
+			continue
+		}
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// leafSettings returns the keys of the settings of type t, which is the type of the
+// setting key, descending into sections but not into lists or mappings
+func leafSettings(t reflect.Type, key string) []string {
+	if t.Kind() != reflect.Struct {
+		return []string{key}
+	}
+	var keys []string
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+		if name == "-" || !field.IsExported() {
+			continue
+		}
+		keys = append(keys, leafSettings(field.Type, joinKey(key, name))...)
+	}
+	return keys
+}
+
+// coversSetting reports whether locked names one of the settings in keys, or a section
+// holding one
+func coversSetting(locked string, keys []string) bool {
+	for _, key := range keys {
+		if key == locked || strings.HasPrefix(key, locked+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// IsLocked reports whether a setting is locked by the system config file
+func (c *Config) IsLocked(key string) bool {
+	for _, locked := range c.Locked {
+		if key == locked || strings.HasPrefix(key, locked+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckUnlocked returns an error if a setting, such as github.org, is locked by the system
+// config file, so that commands do not write values to the user config file that would be
+// ignored
+func (c *Config) CheckUnlocked(key string) error {
+	if !c.IsLocked(key) {
+		return nil
+	}
+	return fmt.Errorf("%s is locked by %s", key, systemConfigFile())
+}
+
+// applyLocks sets the locked settings to their values in the system config file, or their
+// defaults if it leaves them out, and records a warning for each value that is ignored
+func (c *Config) applyLocks(v *viper.Viper, settingDefaults map[string]any) {
+	c.Locked = nil
+	system := c.file(SystemScope)
+	if system == nil {
+		return
+	}
+	if locked, ok := system.values["locked"].([]any); ok {
+		for _, key := range locked {
+			c.Locked = append(c.Locked, fmt.Sprint(key))
+		}
+	}
+
+	for _, key := range Settings() {
+		if !c.IsLocked(key) {
+			continue
+		}
+		value, ok := lookupValue(system.values, key)
+		if !ok {
+			value = settingDefaults[key]
+		}
+		ignored := func(where string) {
+			c.warnings = append(c.warnings, fmt.Sprintf("%s is locked by %s; ignoring the value in %s", key, system.Path, where))
+		}
+		for _, f := range c.files {
+			if f.Scope == SystemScope {
+				continue
+			}
+			if _, ok := lookupValue(f.values, key); ok {
+				ignored(f.Path)
+			}
+			if c.Profile != "" {
+				if _, ok := lookupValue(f.values, "profiles."+c.Profile+"."+key); ok {
+					ignored(fmt.Sprintf("profile %s in %s", c.Profile, f.Path))
+				}
+			}
+		}
+		if env := envFor(key); env != "" && os.Getenv(env) != "" {
+			ignored(env)
+		}
+		v.Set(key, value)
+	}
+}
+
+// file returns the loaded config file of a scope, or nil if there is none
+func (c *Config) file(scope string) *File {
+	for i := range c.files {
+		if c.files[i].Scope == scope {
+			return &c.files[i]
+		}
+	}
+	return nil
+}
+
+// envFor returns the environment variable that overrides a setting, if any
+func envFor(key string) string {
+	for _, b := range envBindings {
+		if b.key == key {
+			return b.env
+		}
+	}
+	return ""
+}
+
+// lookupValue returns the value of the setting key, such as github.org, in the settings
+// read from a config file
+func lookupValue(values map[string]any, key string) (any, bool) {
+	var value any = values
+	for _, name := range strings.Split(key, ".") {
+		m, ok := value.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		if value, ok = m[name]; !ok {
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+// Origin describes where the value of a setting comes from
+type Origin struct {
+	// Scope is the scope of the config file the value was read from, or "env" or "default"
+	Scope string
+	// Path is the config file or environment variable the value was read from
+	Path string
+	// Profile is the profile in the config file the value was read from, if any
+	Profile string
+	// Locked is set if the setting is locked by the system config file
+	Locked bool
+}
+
+func (o Origin) String() string {
+	s := o.Scope
+	if o.Path != "" {
+		s += " " + o.Path
+	}
+	if o.Profile != "" {
+		s += ", profile " + o.Profile
+	}
+	if o.Locked {
+		s += " (locked)"
+	}
+	return s
+}
+
+// Origin returns where the value of a setting, such as github.org, comes from: the file
+// with the highest precedence that sets it, the environment variable that overrides it, or
+// its default
+func (c *Config) Origin(key string) Origin {
+	if c.IsLocked(key) {
+		if system := c.file(SystemScope); system != nil {
+			if _, ok := lookupValue(system.values, key); ok {
+				return Origin{Scope: SystemScope, Path: system.Path, Locked: true}
+			}
+		}
+		return Origin{Scope: "default", Locked: true}
+	}
+	if env := envFor(key); env != "" && os.Getenv(env) != "" {
+		return Origin{Scope: "env", Path: env}
+	}
+	if c.Profile != "" {
+		for i := len(c.files) - 1; i >= 0; i-- {
+			if _, ok := lookupValue(c.files[i].values, "profiles."+c.Profile+"."+key); ok {
+				return Origin{Scope: c.files[i].Scope, Path: c.files[i].Path, Profile: c.Profile}
+			}
+		}
+	}
+	for i := len(c.files) - 1; i >= 0; i-- {
+		if _, ok := lookupValue(c.files[i].values, key); ok {
+			return Origin{Scope: c.files[i].Scope, Path: c.files[i].Path}
+		}
+	}
+	return Origin{Scope: "default"}
+}
+
+// Value returns the value of a setting, such as github.org, formatted for display
+func (c *Config) Value(key string) string {
+	value := reflect.ValueOf(*c)
+	for _, name := range strings.Split(key, ".") {
+		field, ok := yamlFields(value.Type())[name]
+		if !ok {
+			return ""
+		}
+		value = value.FieldByIndex(field.Index)
+	}
+
+	switch value.Kind() {
+	case reflect.Slice:
+		items := make([]string, value.Len())
+		for i := range items {
+			items[i] = fmt.Sprint(value.Index(i).Interface())
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	case reflect.Map:
+		var items []string
+		for _, k := range value.MapKeys() {
+			items = append(items, fmt.Sprintf("%v: %v", k.Interface(), value.MapIndex(k).Interface()))
+		}
+		sort.Strings(items)
+		return "{" + strings.Join(items, ", ") + "}"
+	default:
+		return fmt.Sprint(value.Interface())
+	}
+}
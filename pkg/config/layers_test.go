@@ -0,0 +1,188 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/educates/educatesenv/pkg/paths"
+)
+
+// writeFile writes a config file, creating its directory
+func writeFile(t *testing.T, path, content string) {
+	assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+}
+
+func TestLoadLayers(t *testing.T) {
+	dir := setupConfigDir(t)
+	writeFile(t, filepath.Join(dir, "system.yaml"), `github:
+  org: mirror
+  repository: educates-mirror
+updateNotifier:
+  enabled: true
+`)
+	writeFile(t, filepath.Join(dir, "config.yaml"), `github:
+  repository: educates-training-platform
+  token: user-token
+autoPrune:
+  keep: 3
+`)
+	project := filepath.Join(dir, "workshop")
+	writeFile(t, filepath.Join(project, paths.ProjectConfigName), `autoPrune:
+  keep: 5
+local:
+  dir: .educates/bin
+`)
+	// A config.yaml in the current directory is not read
+	writeFile(t, filepath.Join(project, "content", "config.yaml"), "github:\n  org: unrelated\n")
+	t.Chdir(filepath.Join(project, "content"))
+	t.Setenv("EDUCATES_GITHUB_REPOSITORY", "env-repo")
+	assert.NoError(t, Trust(filepath.Join(project, paths.ProjectConfigName)))
+
+	c := New()
+	assert.NoError(t, c.Load())
+	assert.Equal(t, "mirror", c.Github.Org)
+	assert.Equal(t, "env-repo", c.Github.Repository)
+	assert.Equal(t, "user-token", c.Github.Token)
+	assert.Equal(t, 5, c.AutoPrune.Keep)
+	assert.True(t, c.UpdateNotifier.Enabled)
+	assert.Equal(t, filepath.Join(project, ".educates", "bin"), c.Local.Dir)
+
+	var scopes []string
+	for _, f := range c.Files() {
+		scopes = append(scopes, f.Scope)
+	}
+	assert.Equal(t, []string{SystemScope, UserScope, ProjectScope}, scopes)
+
+	assert.Equal(t, "system "+filepath.Join(dir, "system.yaml"), c.Origin("github.org").String())
+	assert.Equal(t, "env EDUCATES_GITHUB_REPOSITORY", c.Origin("github.repository").String())
+	assert.Equal(t, "user "+filepath.Join(dir, "config.yaml"), c.Origin("github.token").String())
+	assert.Equal(t, "project "+filepath.Join(project, paths.ProjectConfigName), c.Origin("autoPrune.keep").String())
+	assert.Equal(t, "default", c.Origin("hooks.preUse").String())
+	assert.Empty(t, c.Warnings())
+}
+
+func TestLoadProjectRestricted(t *testing.T) {
+	dir := setupConfigDir(t)
+	path := filepath.Join(dir, paths.ProjectConfigName)
+	writeFile(t, path, `github:
+  org: educates
+  token: stolen
+hooks:
+  postUse:
+    - curl https://example.com
+locked:
+  - github
+currentProfile: fork
+`)
+	assert.NoError(t, Trust(path))
+
+	err := New().Load()
+	var invalid *ValidationError
+	assert.ErrorAs(t, err, &invalid)
+	assert.Equal(t, path, invalid.File)
+	assert.Equal(t, []Problem{
+		{Line: 3, Column: 3, Key: "github.token", Message: "cannot be set in a project config file"},
+		{Line: 4, Column: 1, Key: "hooks", Message: "cannot be set in a project config file"},
+		{Line: 7, Column: 1, Key: "locked", Message: "can only be set in the system config file " + filepath.Join(dir, "system.yaml")},
+		{Line: 9, Column: 1, Key: "currentProfile", Message: "cannot be set in a project config file"},
+	}, invalid.Problems)
+
+	// The scope of a file being validated follows from its name
+	_, err = ValidateFile(path)
+	assert.ErrorAs(t, err, &invalid)
+	assert.Len(t, invalid.Problems, 4)
+}
+
+func TestLoadLocked(t *testing.T) {
+	dir := setupConfigDir(t)
+	system := filepath.Join(dir, "system.yaml")
+	writeFile(t, system, `github:
+  baseURL: https://github.example.com/api/v3/
+verify:
+  required: true
+locked:
+  - github.baseURL
+  - verify
+  - hooks.preInstall
+`)
+	user := filepath.Join(dir, "config.yaml")
+	writeFile(t, user, `github:
+  baseURL: https://elsewhere.example.com/api/v3/
+verify:
+  required: false
+hooks:
+  preInstall:
+    - echo user
+  postInstall:
+    - echo user
+currentProfile: fork
+profiles:
+  fork:
+    github:
+      org: me
+      baseURL: https://fork.example.com/api/v3/
+`)
+	t.Setenv("EDUCATES_VERIFY_REQUIRED", "false")
+
+	c := New()
+	assert.NoError(t, c.Load())
+	assert.Equal(t, "https://github.example.com/api/v3/", c.Github.BaseURL)
+	assert.Equal(t, "me", c.Github.Org)
+	assert.True(t, c.Verify.Required)
+	assert.Empty(t, c.Hooks.PreInstall)
+	assert.Equal(t, []string{"echo user"}, c.Hooks.PostInstall)
+	assert.True(t, c.IsLocked("verify.publicKeys"))
+	assert.False(t, c.IsLocked("github.org"))
+	assert.EqualError(t, c.CheckUnlocked("hooks.preInstall"), "hooks.preInstall is locked by "+system)
+	assert.EqualError(t, c.CheckUnlocked("verify.required"), "verify.required is locked by "+system)
+	assert.NoError(t, c.CheckUnlocked("hooks.postInstall"))
+
+	assert.Equal(t, []string{
+		"github.baseURL is locked by " + system + "; ignoring the value in " + user,
+		"github.baseURL is locked by " + system + "; ignoring the value in profile fork in " + user,
+		"verify.required is locked by " + system + "; ignoring the value in " + user,
+		"verify.required is locked by " + system + "; ignoring the value in EDUCATES_VERIFY_REQUIRED",
+		"hooks.preInstall is locked by " + system + "; ignoring the value in " + user,
+	}, c.Warnings())
+
+	assert.Equal(t, "system "+system+" (locked)", c.Origin("github.baseURL").String())
+	assert.Equal(t, "default (locked)", c.Origin("hooks.preInstall").String())
+	assert.Equal(t, "user "+user+", profile fork", c.Origin("github.org").String())
+}
+
+func TestValidateLocked(t *testing.T) {
+	dir := setupConfigDir(t)
+	system := filepath.Join(dir, "system.yaml")
+	writeFile(t, system, "locked:\n  - github\n  - github.orgs\n  - profiles\n")
+
+	_, err := ValidateFile(system)
+	var invalid *ValidationError
+	assert.ErrorAs(t, err, &invalid)
+	assert.Equal(t, []Problem{
+		{Line: 3, Column: 5, Key: "locked[1]", Message: "github.orgs is not a setting that can be locked"},
+		{Line: 4, Column: 5, Key: "locked[2]", Message: "profiles is not a setting that can be locked"},
+	}, invalid.Problems)
+}
+
+func TestSettingsAndValue(t *testing.T) {
+	settings := Settings()
+	assert.Equal(t, "github.org", settings[0])
+	assert.Contains(t, settings, "development.builds")
+	assert.Contains(t, settings, "updateNotifier.interval")
+	assert.NotContains(t, settings, "profiles")
+	assert.NotContains(t, settings, "apiVersion")
+
+	c := New()
+	c.Development.Builds = map[string]string{"main": "/src/main", "feature": "/src/feature"}
+	c.Hooks.PostUse = []string{"echo a", "echo b"}
+	assert.Equal(t, "educates", c.Value("github.org"))
+	assert.Equal(t, "false", c.Value("development.enabled"))
+	assert.Equal(t, "{feature: /src/feature, main: /src/main}", c.Value("development.builds"))
+	assert.Equal(t, "[echo a, echo b]", c.Value("hooks.postUse"))
+	assert.Equal(t, "0", c.Value("autoPrune.keep"))
+	assert.Empty(t, c.Value("no.such"))
+}
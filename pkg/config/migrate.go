@@ -0,0 +1,201 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/educates/educatesenv/pkg/paths"
+	"github.com/educates/educatesenv/pkg/platform"
+)
+
+// MigrateLegacy moves the files of the legacy single directory layout in legacyDir to
+// their places in layout, and returns a description of each move. Nothing is done if the
+// layout is a single directory, if there is nothing to migrate, or once the config file
+// exists in layout. An entry whose destination already exists is left where it is.
+//
+// Paths in the config file and development builds that point into the moved directories
+// are updated, the link to the active version is repointed, and the legacy bin directory
+// is replaced by a symlink to the new one, so that a PATH that includes it keeps working.
+func MigrateLegacy(layout paths.Layout, legacyDir string) ([]string, error) {
+	if layout.Single || exists(layout.ConfigFile()) {
+		return nil, nil
+	}
+	legacyBin := filepath.Join(legacyDir, "bin")
+	legacyConfig := filepath.Join(legacyDir, "config.yaml")
+	if !isRealDir(legacyBin) && !exists(legacyConfig) {
+		return nil, nil
+	}
+
+	// The development builds are moved before the bin directory, which may link to one of
+	// them, and the config file is moved last, so that an interrupted migration is resumed
+	// and the paths in it are only rewritten for the directories that were moved
+	moves := []struct {
+		from, to string
+		// relocated is set if paths into the directory are rewritten once it is moved
+		relocated bool
+	}{
+		{filepath.Join(legacyDir, "dev"), layout.DevBuildsDir(), true},
+		{filepath.Join(legacyDir, "plugins"), layout.PluginsDir(), true},
+		{legacyBin, layout.BinDir(), true},
+		{filepath.Join(legacyDir, "cache"), layout.CacheDir(), false},
+		{filepath.Join(legacyDir, "update-check.json"), layout.UpdateCheckFile(), false},
+		{filepath.Join(legacyDir, "aliases.yaml"), layout.AliasesFile(), false},
+		{legacyConfig, layout.ConfigFile(), false},
+	}
+
+	// relocations maps the directories that were moved to their new locations
+	relocations := map[string]string{}
+	var moved []string
+	for _, move := range moves {
+		if !exists(move.from) || (move.from == legacyBin && !isRealDir(legacyBin)) {
+			continue
+		}
+		if exists(move.to) {
+			moved = append(moved, fmt.Sprintf("left %s in place, as %s already exists", move.from, move.to))
+			continue
+		}
+
+		if move.from == legacyConfig {
+			if err := relocateConfig(legacyConfig, relocations); err != nil {
+				return moved, err
+			}
+		}
+		if err := os.MkdirAll(filepath.Dir(move.to), 0o755); err != nil {
+			return moved, fmt.Errorf("failed to create directory for %s: %w", move.to, err)
+		}
+		if err := os.Rename(move.from, move.to); err != nil {
+			// Another educatesenv may be migrating at the same time
+			if os.IsNotExist(err) {
+				continue
+			}
+			return moved, fmt.Errorf("failed to move %s to %s: %w", move.from, move.to, err)
+		}
+		moved = append(moved, fmt.Sprintf("moved %s to %s", move.from, move.to))
+		if move.relocated {
+			relocations[move.from] = move.to
+		}
+
+		switch move.from {
+		case legacyBin:
+			if err := relocateBin(legacyBin, move.to, relocations); err != nil {
+				return moved, err
+			}
+		case filepath.Join(legacyDir, "dev"):
+			relocateDevBuilds(move.to, relocations)
+		}
+	}
+	return moved, nil
+}
+
+// relocateConfig rewrites the paths in the config file that point into relocated directories
+func relocateConfig(path string, relocations map[string]string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file %s: %w", path, err)
+	}
+	var c Config
+	if err := yaml.Unmarshal(data, &c); err != nil {
+		return fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	keys := [][]string{{"local", "dir"}, {"development", "binaryLocation"}}
+	current := []string{c.Local.Dir, c.Development.BinaryLocation}
+	for name, binary := range c.Development.Builds {
+		keys = append(keys, []string{"development", "builds", name})
+		current = append(current, binary)
+	}
+	for i, value := range current {
+		p, ok := relocate(value, relocations)
+		if !ok {
+			continue
+		}
+		if err := SetValue(path, keys[i], p); err != nil {
+			return fmt.Errorf("failed to update %s in %s: %w", strings.Join(keys[i], "."), path, err)
+		}
+	}
+	return nil
+}
+
+// relocateBin repoints the link to the active version, which is relative to the bin
+// directory, to where its target is after the migration, and leaves a symlink to the new
+// bin directory in place of the old one
+func relocateBin(legacyBin, bin string, relocations map[string]string) error {
+	link := filepath.Join(bin, platform.ActiveBinaryBaseName)
+	if target, err := os.Readlink(link); err == nil {
+		resolved := target
+		if !filepath.IsAbs(resolved) {
+			resolved = filepath.Join(legacyBin, resolved)
+		}
+		if p, ok := relocate(resolved, relocations); ok {
+			resolved = p
+		}
+		if p, err := filepath.Rel(bin, resolved); err == nil && p != target {
+			tmp := link + ".migrate"
+			_ = os.Remove(tmp)
+			if err := os.Symlink(p, tmp); err != nil {
+				return fmt.Errorf("failed to repoint %s: %w", link, err)
+			}
+			if err := os.Rename(tmp, link); err != nil {
+				_ = os.Remove(tmp)
+				return fmt.Errorf("failed to repoint %s: %w", link, err)
+			}
+		}
+	}
+	if err := os.Symlink(bin, legacyBin); err != nil {
+		return fmt.Errorf("failed to link %s to %s: %w", legacyBin, bin, err)
+	}
+	return nil
+}
+
+// relocateDevBuilds rewrites the binary paths recorded for development builds. The records
+// are only informational, so failures are ignored.
+func relocateDevBuilds(devDir string, relocations map[string]string) {
+	records, _ := filepath.Glob(filepath.Join(devDir, "*", "build.json"))
+	for _, record := range records {
+		data, err := os.ReadFile(record)
+		if err != nil {
+			continue
+		}
+		var info map[string]any
+		if err := json.Unmarshal(data, &info); err != nil {
+			continue
+		}
+		binary, _ := info["binary"].(string)
+		if p, ok := relocate(binary, relocations); ok {
+			info["binary"] = p
+			if data, err := json.MarshalIndent(info, "", "  "); err == nil {
+				_ = os.WriteFile(record, data, 0o644)
+			}
+		}
+	}
+}
+
+// relocate returns path with a relocated directory prefix replaced by its new location
+func relocate(path string, relocations map[string]string) (string, bool) {
+	for from, to := range relocations {
+		if path == from {
+			return to, true
+		}
+		if rest, ok := strings.CutPrefix(path, from+string(filepath.Separator)); ok {
+			return filepath.Join(to, rest), true
+		}
+	}
+	return "", false
+}
+
+// exists reports whether path exists, without following a final symlink
+func exists(path string) bool {
+	_, err := os.Lstat(path)
+	return err == nil
+}
+
+// isRealDir reports whether path is a directory and not a symlink to one
+func isRealDir(path string) bool {
+	fi, err := os.Lstat(path)
+	return err == nil && fi.IsDir()
+}
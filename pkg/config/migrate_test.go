@@ -0,0 +1,163 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/educates/educatesenv/pkg/paths"
+)
+
+func TestMigrateLegacy(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the legacy layout is not migrated on Windows")
+	}
+
+	home := t.TempDir()
+	legacy := filepath.Join(home, ".educatesenv")
+	layout := paths.Layout{
+		Config: filepath.Join(home, "config"),
+		Data:   filepath.Join(home, "data"),
+		Cache:  filepath.Join(home, "cache"),
+		State:  filepath.Join(home, "state"),
+	}
+
+	write := func(path, content string) {
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		assert.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+	write(filepath.Join(legacy, "bin", "educates-3.2.1"), "binary")
+	write(filepath.Join(legacy, "dev", "main", "educates"), "dev binary")
+	write(filepath.Join(legacy, "dev", "main", "build.json"), `{"name": "main", "binary": "`+filepath.Join(legacy, "dev", "main", "educates")+`"}`)
+	write(filepath.Join(legacy, "cache", "releases.json"), "{}")
+	write(filepath.Join(legacy, "aliases.yaml"), "aliases: {}\n")
+	write(filepath.Join(legacy, "config.yaml"), `# my settings
+local:
+  dir: `+filepath.Join(legacy, "bin")+`
+development:
+  enabled: true
+  builds:
+    main: `+filepath.Join(legacy, "dev", "main", "educates")+`
+    other: /src/educates
+`)
+	assert.NoError(t, os.Symlink("../dev/main/educates", filepath.Join(legacy, "bin", "educates")))
+	write(layout.CacheDir(), "in the way")
+
+	moved, err := MigrateLegacy(layout, legacy)
+	assert.NoError(t, err)
+	assert.Contains(t, moved, "moved "+filepath.Join(legacy, "bin")+" to "+layout.BinDir())
+	assert.Contains(t, moved, "left "+filepath.Join(legacy, "cache")+" in place, as "+layout.CacheDir()+" already exists")
+
+	assert.FileExists(t, filepath.Join(layout.BinDir(), "educates-3.2.1"))
+	assert.FileExists(t, filepath.Join(layout.DevBuildsDir(), "main", "educates"))
+	assert.FileExists(t, layout.AliasesFile())
+	assert.FileExists(t, filepath.Join(legacy, "cache", "releases.json"))
+
+	// Paths into the legacy directory are rewritten, and comments are kept
+	data, err := os.ReadFile(layout.ConfigFile())
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), "# my settings")
+	assert.Contains(t, string(data), "dir: "+layout.BinDir())
+	assert.Contains(t, string(data), "main: "+filepath.Join(layout.DevBuildsDir(), "main", "educates"))
+	assert.Contains(t, string(data), "other: /src/educates")
+
+	data, err = os.ReadFile(filepath.Join(layout.DevBuildsDir(), "main", "build.json"))
+	assert.NoError(t, err)
+	var info map[string]any
+	assert.NoError(t, json.Unmarshal(data, &info))
+	assert.Equal(t, filepath.Join(layout.DevBuildsDir(), "main", "educates"), info["binary"])
+
+	// The active link still resolves, and the old bin directory leads to the new one
+	content, err := os.ReadFile(filepath.Join(layout.BinDir(), "educates"))
+	assert.NoError(t, err)
+	assert.Equal(t, "dev binary", string(content))
+	target, err := os.Readlink(filepath.Join(legacy, "bin"))
+	assert.NoError(t, err)
+	assert.Equal(t, layout.BinDir(), target)
+
+	// Once migrated, nothing more is done
+	moved, err = MigrateLegacy(layout, legacy)
+	assert.NoError(t, err)
+	assert.Empty(t, moved)
+}
+
+func TestMigrateLegacyRepointsActiveLink(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the legacy layout is not migrated on Windows")
+	}
+
+	home := t.TempDir()
+	legacy := filepath.Join(home, ".educatesenv")
+	layout := paths.Layout{
+		Config: filepath.Join(home, "xdg", "config"),
+		Data:   filepath.Join(home, "xdg", "share", "data"),
+		Cache:  filepath.Join(home, "xdg", "cache"),
+		State:  filepath.Join(home, "xdg", "state"),
+	}
+	external := filepath.Join(home, "src", "educates")
+	assert.NoError(t, os.MkdirAll(filepath.Dir(external), 0o755))
+	assert.NoError(t, os.WriteFile(external, []byte("checkout binary"), 0o755))
+	assert.NoError(t, os.MkdirAll(filepath.Join(legacy, "bin"), 0o755))
+	rel, err := filepath.Rel(filepath.Join(legacy, "bin"), external)
+	assert.NoError(t, err)
+	assert.NoError(t, os.Symlink(rel, filepath.Join(legacy, "bin", "educates")))
+
+	_, err = MigrateLegacy(layout, legacy)
+	assert.NoError(t, err)
+	content, err := os.ReadFile(filepath.Join(layout.BinDir(), "educates"))
+	assert.NoError(t, err)
+	assert.Equal(t, "checkout binary", string(content))
+}
+
+func TestMigrateLegacyBinInTheWay(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the legacy layout is not migrated on Windows")
+	}
+
+	home := t.TempDir()
+	legacy := filepath.Join(home, ".educatesenv")
+	layout := paths.Layout{
+		Config: filepath.Join(home, "config"),
+		Data:   filepath.Join(home, "data"),
+		Cache:  filepath.Join(home, "cache"),
+		State:  filepath.Join(home, "state"),
+	}
+	assert.NoError(t, os.MkdirAll(filepath.Join(legacy, "bin"), 0o755))
+	assert.NoError(t, os.WriteFile(filepath.Join(legacy, "bin", "educates-3.2.1"), []byte("binary"), 0o755))
+	assert.NoError(t, os.WriteFile(filepath.Join(legacy, "config.yaml"), []byte("local:\n  dir: "+filepath.Join(legacy, "bin")+"\n"), 0o644))
+	// make install creates the new bin directory before the first run
+	assert.NoError(t, os.MkdirAll(layout.BinDir(), 0o755))
+
+	moved, err := MigrateLegacy(layout, legacy)
+	assert.NoError(t, err)
+	assert.Contains(t, moved, "left "+filepath.Join(legacy, "bin")+" in place, as "+layout.BinDir()+" already exists")
+	assert.Contains(t, moved, "moved "+filepath.Join(legacy, "config.yaml")+" to "+layout.ConfigFile())
+
+	// The config file keeps pointing at the installed versions
+	data, err := os.ReadFile(layout.ConfigFile())
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), "dir: "+filepath.Join(legacy, "bin"))
+	assert.True(t, isRealDir(filepath.Join(legacy, "bin")))
+	assert.FileExists(t, filepath.Join(legacy, "bin", "educates-3.2.1"))
+}
+
+func TestMigrateLegacySkipped(t *testing.T) {
+	home := t.TempDir()
+	legacy := filepath.Join(home, ".educatesenv")
+	assert.NoError(t, os.MkdirAll(filepath.Join(legacy, "bin"), 0o755))
+
+	// A single directory layout is never migrated
+	moved, err := MigrateLegacy(paths.Layout{Config: legacy, Data: legacy, Cache: legacy, State: legacy, Single: true}, legacy)
+	assert.NoError(t, err)
+	assert.Empty(t, moved)
+
+	// Nor is anything done without a legacy directory
+	layout := paths.Layout{Config: filepath.Join(home, "c"), Data: filepath.Join(home, "d"), Cache: filepath.Join(home, "x"), State: filepath.Join(home, "s")}
+	moved, err = MigrateLegacy(layout, filepath.Join(home, "missing"))
+	assert.NoError(t, err)
+	assert.Empty(t, moved)
+}
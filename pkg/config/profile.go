@@ -0,0 +1,59 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+)
+
+const (
+	// ProfileEnv names the environment variable that selects a profile, overriding currentProfile
+	ProfileEnv = "EDUCATESENV_PROFILE"
+	// DefaultProfile names the top-level settings, used when no profile is selected
+	DefaultProfile = "default"
+)
+
+// profileNamePattern matches valid profile names, which are used as keys in the config file
+var profileNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// ProfileConfig holds the settings a profile replaces. Settings it leaves out keep their
+// top-level values, and its development builds are added to the top-level ones.
+type ProfileConfig struct {
+	Github      GithubConfig      `yaml:"github,omitempty"`
+	Local       LocalConfig       `yaml:"local,omitempty"`
+	Development DevelopmentConfig `yaml:"development,omitempty"`
+}
+
+// ValidateProfileName checks that name can be used for a profile
+func ValidateProfileName(name string) error {
+	if name == DefaultProfile {
+		return fmt.Errorf("%s is reserved for the top-level settings", DefaultProfile)
+	}
+	if !profileNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid profile name %q: use lowercase letters, digits, '_' and '-'", name)
+	}
+	return nil
+}
+
+// ProfileNames returns the names of the profiles defined in the config file, sorted
+func (c *Config) ProfileNames() []string {
+	names := make([]string, 0, len(c.Profiles))
+	for name := range c.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SettingPath returns the keys a setting, such as github.org, is written to in the config
+// file. While a profile is in use, the settings a profile can hold are written to it.
+func (c *Config) SettingPath(keys ...string) []string {
+	if c.Profile == "" {
+		return keys
+	}
+	switch keys[0] {
+	case "github", "local", "development":
+		return append([]string{"profiles", c.Profile}, keys...)
+	}
+	return keys
+}
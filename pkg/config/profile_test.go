@@ -0,0 +1,130 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateProfileName(t *testing.T) {
+	assert.NoError(t, ValidateProfileName("ghe"))
+	assert.NoError(t, ValidateProfileName("fork_2"))
+	assert.Error(t, ValidateProfileName("default"))
+	assert.Error(t, ValidateProfileName("GHE"))
+	assert.Error(t, ValidateProfileName("ghe.fork"))
+	assert.Error(t, ValidateProfileName(""))
+}
+
+func TestSettingPath(t *testing.T) {
+	c := New()
+	assert.Equal(t, []string{"github", "org"}, c.SettingPath("github", "org"))
+
+	c.Profile = "ghe"
+	assert.Equal(t, []string{"profiles", "ghe", "github", "org"}, c.SettingPath("github", "org"))
+	assert.Equal(t, []string{"profiles", "ghe", "development", "builds", "main"}, c.SettingPath("development", "builds", "main"))
+	assert.Equal(t, []string{"hooks", "postUse"}, c.SettingPath("hooks", "postUse"))
+}
+
+func TestLoadProfile(t *testing.T) {
+	tmpDir := setupConfigDir(t)
+	err := os.WriteFile(filepath.Join(tmpDir, "config.yaml"), []byte(`apiVersion: educatesenv/v1
+currentProfile: fork
+github:
+  org: educates
+  repository: educates-training-platform
+local:
+  dir: /opt/educates/bin
+development:
+  builds:
+    main: /src/main
+profiles:
+  ghe:
+    github:
+      org: platform
+      token: ghe-token
+      baseURL: https://github.example.com/api/v3/
+    local:
+      dir: /opt/ghe/bin
+    development:
+      builds:
+        feature: /src/feature
+  fork:
+    github:
+      org: me
+`), 0o644)
+	assert.NoError(t, err)
+
+	// currentProfile is used by default
+	c := New()
+	assert.NoError(t, c.Load())
+	assert.Equal(t, "fork", c.Profile)
+	assert.Equal(t, "me", c.Github.Org)
+	assert.Equal(t, "educates-training-platform", c.Github.Repository)
+	assert.Equal(t, "/opt/educates/bin", c.Local.Dir)
+	assert.Equal(t, []string{"fork", "ghe"}, c.ProfileNames())
+
+	// EDUCATESENV_PROFILE overrides it, and a profile set before Load overrides both
+	t.Setenv(ProfileEnv, "ghe")
+	c = New()
+	assert.NoError(t, c.Load())
+	assert.Equal(t, "ghe", c.Profile)
+	assert.Equal(t, "platform", c.Github.Org)
+	assert.Equal(t, "educates-training-platform", c.Github.Repository)
+	assert.Equal(t, "ghe-token", c.Github.Token)
+	assert.Equal(t, "https://github.example.com/api/v3/", c.Github.BaseURL)
+	assert.Equal(t, "/opt/ghe/bin", c.Local.Dir)
+	assert.Equal(t, map[string]string{"main": "/src/main", "feature": "/src/feature"}, c.Development.Builds)
+
+	c = New()
+	c.Profile = DefaultProfile
+	assert.NoError(t, c.Load())
+	assert.Empty(t, c.Profile)
+	assert.Equal(t, "educates", c.Github.Org)
+	assert.Empty(t, c.Github.BaseURL)
+	assert.Equal(t, map[string]string{"main": "/src/main"}, c.Development.Builds)
+
+	// Environment variables for settings override the profile
+	t.Setenv("EDUCATES_GITHUB_ORG", "envorg")
+	c = New()
+	assert.NoError(t, c.Load())
+	assert.Equal(t, "envorg", c.Github.Org)
+	assert.Equal(t, "ghe-token", c.Github.Token)
+
+	c = New()
+	c.Profile = "missing"
+	assert.ErrorContains(t, c.Load(), "profile missing is not defined")
+}
+
+func TestLoadInvalidBaseURL(t *testing.T) {
+	tmpDir := setupConfigDir(t)
+	err := os.WriteFile(filepath.Join(tmpDir, "config.yaml"), []byte("github:\n  baseURL: github.example.com\n"), 0o644)
+	assert.NoError(t, err)
+
+	assert.ErrorContains(t, New().Load(), "is not an http or https URL")
+}
+
+func TestValidateFileProfiles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	err := os.WriteFile(path, []byte(`profiles:
+  default:
+    github:
+      org: educates
+  ghe:
+    github:
+      orgs: platform
+    hooks:
+      postUse: []
+`), 0o644)
+	assert.NoError(t, err)
+
+	_, err = ValidateFile(path)
+	var invalid *ValidationError
+	assert.ErrorAs(t, err, &invalid)
+	assert.Equal(t, []Problem{
+		{Line: 2, Column: 3, Key: "profiles.default", Message: "default is reserved for the top-level settings"},
+		{Line: 7, Column: 7, Key: "profiles.ghe.github.orgs", Message: "unknown key; did you mean org?"},
+		{Line: 8, Column: 5, Key: "profiles.ghe.hooks", Message: "unknown key; expected one of development, github, local"},
+	}, invalid.Problems)
+}
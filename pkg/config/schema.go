@@ -0,0 +1,337 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// descriptions document the settings in the JSON Schema, by key
+var descriptions = map[string]string{
+	"apiVersion":                 "Version of the config file format. Files written for an earlier version are upgraded when loaded.",
+	"currentProfile":             "Profile used unless another is selected with --profile or EDUCATESENV_PROFILE",
+	"github":                     "GitHub repository educates releases are installed from",
+	"github.org":                 "GitHub organization of the repository",
+	"github.repository":          "Name of the repository",
+	"github.baseURL":             "API URL of a GitHub Enterprise Server, such as https://github.example.com/api/v3/",
+	"github.token":               "Token used for GitHub API requests, to raise the rate limit",
+	"local":                      "Where installed versions are kept",
+	"local.dir":                  "Bin directory holding the installed versions and the link to the active one",
+	"development":                "Development builds of educates, made from local checkouts",
+	"development.enabled":        "Whether development builds can be used",
+	"development.binaryLocation": "Binary of the development build available as develop",
+	"development.builds":         "Binaries of named development builds, used as dev:<name>",
+	"development.buildCommand":   "Shell command run by dev build from the checkout root, which must write the binary to $EDUCATESENV_BUILD_OUTPUT",
+	"verify":                     "Verification of release signatures",
+	"verify.required":            "Fail installs of releases that have no signature",
+	"verify.publicKeys":          "Cosign or minisign public keys release signatures are checked against",
+	"autoPrune":                  "Retention policy applied after successful installs",
+	"autoPrune.enabled":          "Whether to prune after successful installs",
+	"autoPrune.keep":             "Number of newest versions to keep",
+	"autoPrune.olderThan":        "Remove versions installed longer ago than this, such as 90d",
+	"autoPrune.unusedSince":      "Remove versions not used for this long, such as 30d",
+	"hooks":                      "Shell commands run around installs and version switches",
+	"hooks.preInstall":           "Commands run before a version is installed. A failure aborts the install.",
+	"hooks.postInstall":          "Commands run after a version is installed",
+	"hooks.preUse":               "Commands run before the active version changes. A failure aborts the switch.",
+	"hooks.postUse":              "Commands run after the active version changes",
+	"updateNotifier":             "Notice shown when a newer educates release exists",
+	"updateNotifier.enabled":     "Whether to check for newer releases",
+	"updateNotifier.interval":    "Minimum time between checks, such as 24h or 7d",
+	"locked":                     "Settings, or sections of settings, that only the system config file can set. Only allowed in the system config file.",
+	"profiles":                   "Named sets of github, local and development settings, which replace the top-level ones while the profile is in use",
+}
+
+// keyChecks validate the keys of the mappings of settings with names as keys, by setting
+var keyChecks = map[string]func(string) error{
+	"profiles": ValidateProfileName,
+}
+
+// Problem is a setting in a config file that does not match the schema
+type Problem struct {
+	// Line and Column locate the problem in the file
+	Line   int
+	Column int
+	// Key is the setting, such as development.enabled
+	Key     string
+	Message string
+}
+
+func (p Problem) String() string {
+	return fmt.Sprintf("line %d: %s: %s", p.Line, p.Key, p.Message)
+}
+
+// ValidationError lists the problems found in a config file
+type ValidationError struct {
+	File     string
+	Problems []Problem
+}
+
+func (e *ValidationError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "invalid config file %s:", e.File)
+	for _, p := range e.Problems {
+		b.WriteString("\n  " + p.String())
+	}
+	return b.String()
+}
+
+// ValidateFile checks the config file at path against the schema. A file written for an
+// earlier apiVersion is upgraded in memory first. It returns the apiVersion the file was
+// written for, which is empty if the file has none, and a *ValidationError listing every
+// unknown key, value of the wrong type and setting that cannot be set in the file, which
+// depends on whether it is the system, a user or a project config file.
+func ValidateFile(path string) (string, error) {
+	apiVersion, _, err := parseFile(path, scopeOf(path))
+	return apiVersion, err
+}
+
+// parseFile reads and validates the config file at path, which has the given scope. It
+// returns the apiVersion the file was written for and its settings upgraded to
+// CurrentAPIVersion, which are nil if the file is empty.
+func parseFile(path, scope string) (string, *yaml.Node, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return "", nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+	if doc.Kind == 0 || len(doc.Content) == 0 {
+		return "", nil, nil
+	}
+
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return "", nil, &ValidationError{File: path, Problems: []Problem{{Line: root.Line, Column: root.Column, Key: "(root)", Message: "must be a mapping, not " + describeNode(root)}}}
+	}
+	apiVersion, node := apiVersionOf(root)
+	if err := upgrade(root); err != nil {
+		line, column := root.Line, root.Column
+		if node != nil {
+			line, column = node.Line, node.Column
+		}
+		return apiVersion, nil, &ValidationError{File: path, Problems: []Problem{{Line: line, Column: column, Key: "apiVersion", Message: err.Error()}}}
+	}
+	problems := validateNode(root, reflect.TypeOf(Config{}), "")
+	problems = append(problems, scopeProblems(root, scope)...)
+	if len(problems) > 0 {
+		sort.SliceStable(problems, func(i, j int) bool { return problems[i].Line < problems[j].Line })
+		return apiVersion, nil, &ValidationError{File: path, Problems: problems}
+	}
+	return apiVersion, root, nil
+}
+
+// validateNode checks that node holds a value of type t, the type of the setting key
+func validateNode(node *yaml.Node, t reflect.Type, key string) []Problem {
+	if node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	// An empty value leaves the setting at its default
+	if node.Kind == yaml.ScalarNode && node.Tag == "!!null" {
+		return nil
+	}
+
+	mismatch := func(expected string) []Problem {
+		return []Problem{{Line: node.Line, Column: node.Column, Key: key, Message: fmt.Sprintf("must be %s, not %s", expected, describeNode(node))}}
+	}
+	var problems []Problem
+	switch t.Kind() {
+	case reflect.Struct:
+		if node.Kind != yaml.MappingNode {
+			return mismatch("a mapping")
+		}
+		fields := yamlFields(t)
+		seen := map[string]bool{}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			k, v := node.Content[i], node.Content[i+1]
+			sub := joinKey(key, k.Value)
+			field, ok := fields[k.Value]
+			switch {
+			case !ok:
+				problems = append(problems, Problem{Line: k.Line, Column: k.Column, Key: sub, Message: unknownKey(k.Value, fields)})
+			case seen[k.Value]:
+				problems = append(problems, Problem{Line: k.Line, Column: k.Column, Key: sub, Message: "is set more than once"})
+			default:
+				seen[k.Value] = true
+				problems = append(problems, validateNode(v, field.Type, sub)...)
+			}
+		}
+	case reflect.Map:
+		if node.Kind != yaml.MappingNode {
+			return mismatch("a mapping")
+		}
+		seen := map[string]bool{}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			k, v := node.Content[i], node.Content[i+1]
+			sub := joinKey(key, k.Value)
+			if check, ok := keyChecks[key]; ok {
+				if err := check(k.Value); err != nil {
+					problems = append(problems, Problem{Line: k.Line, Column: k.Column, Key: sub, Message: err.Error()})
+					continue
+				}
+			}
+			if seen[k.Value] {
+				problems = append(problems, Problem{Line: k.Line, Column: k.Column, Key: sub, Message: "is set more than once"})
+				continue
+			}
+			seen[k.Value] = true
+			problems = append(problems, validateNode(v, t.Elem(), sub)...)
+		}
+	case reflect.Slice:
+		if node.Kind != yaml.SequenceNode {
+			return mismatch("a list")
+		}
+		for i, item := range node.Content {
+			problems = append(problems, validateNode(item, t.Elem(), fmt.Sprintf("%s[%d]", key, i))...)
+		}
+	case reflect.String:
+		if node.Kind != yaml.ScalarNode || node.Tag != "!!str" {
+			return mismatch("a string")
+		}
+	case reflect.Bool:
+		if node.Kind != yaml.ScalarNode || node.Tag != "!!bool" {
+			return mismatch("true or false")
+		}
+	case reflect.Int:
+		if node.Kind != yaml.ScalarNode || node.Tag != "!!int" {
+			return mismatch("an integer")
+		}
+	}
+	return problems
+}
+
+// JSONSchema returns a JSON Schema of the config file, which editors can use to validate
+// and complete it
+func JSONSchema() ([]byte, error) {
+	schema := schemaFor(reflect.TypeOf(Config{}), "")
+	schema["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+	schema["title"] = "educatesenv configuration"
+	return json.MarshalIndent(schema, "", "  ")
+}
+
+// schemaFor returns the JSON Schema of type t, the type of the setting key
+func schemaFor(t reflect.Type, key string) map[string]any {
+	schema := map[string]any{}
+	if description, ok := descriptions[key]; ok {
+		schema["description"] = description
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		properties := map[string]any{}
+		for name, field := range yamlFields(t) {
+			properties[name] = schemaFor(field.Type, joinKey(key, name))
+		}
+		schema["type"] = "object"
+		schema["properties"] = properties
+		schema["additionalProperties"] = false
+	case reflect.Map:
+		schema["type"] = "object"
+		// The settings of a profile are described like the top-level ones they replace
+		schema["additionalProperties"] = schemaFor(t.Elem(), "")
+		if key == "profiles" {
+			schema["propertyNames"] = map[string]any{
+				"pattern": profileNamePattern.String(),
+				"not":     map[string]any{"const": DefaultProfile},
+			}
+		}
+	case reflect.Slice:
+		schema["type"] = "array"
+		schema["items"] = schemaFor(t.Elem(), "")
+	case reflect.String:
+		schema["type"] = "string"
+	case reflect.Bool:
+		schema["type"] = "boolean"
+	case reflect.Int:
+		schema["type"] = "integer"
+		schema["minimum"] = 0
+	}
+	if key == "apiVersion" {
+		schema["enum"] = APIVersions()
+	}
+	return schema
+}
+
+// yamlFields returns the fields of a struct type by their key in the config file
+func yamlFields(t reflect.Type) map[string]reflect.StructField {
+	fields := map[string]reflect.StructField{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+		if name == "-" || !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(field.Name)
+		}
+		fields[name] = field
+	}
+	return fields
+}
+
+// unknownKey describes an unknown key, suggesting a known key it may be a misspelling of
+func unknownKey(key string, fields map[string]reflect.StructField) string {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	best, bestDistance := "", 3
+	for _, name := range names {
+		if d := editDistance(strings.ToLower(key), strings.ToLower(name)); d < bestDistance {
+			best, bestDistance = name, d
+		}
+	}
+	if best != "" {
+		return fmt.Sprintf("unknown key; did you mean %s?", best)
+	}
+	return fmt.Sprintf("unknown key; expected one of %s", strings.Join(names, ", "))
+}
+
+// editDistance returns the Levenshtein distance between a and b
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
+
+// describeNode describes a YAML value for messages
+func describeNode(node *yaml.Node) string {
+	switch node.Kind {
+	case yaml.MappingNode:
+		return "a mapping"
+	case yaml.SequenceNode:
+		return "a list"
+	default:
+		return fmt.Sprintf("%q", node.Value)
+	}
+}
+
+// joinKey appends name to the dotted key of its parent
+func joinKey(parent, name string) string {
+	if parent == "" {
+		return name
+	}
+	return parent + "." + name
+}
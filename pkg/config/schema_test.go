@@ -0,0 +1,118 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	err := os.WriteFile(path, []byte(`apiVersion: educatesenv/v1
+github:
+  org: educates
+developmet:
+  enabled: true
+development:
+  enabled: yes please
+  builds:
+    main: /src/main
+    broken: [a, b]
+autoPrune:
+  keep: three
+  olderThan:
+hooks:
+  postUse: echo done
+  preUse:
+    - echo
+    - 1
+  onFailure: []
+`), 0o644)
+	assert.NoError(t, err)
+
+	apiVersion, err := ValidateFile(path)
+	assert.Equal(t, CurrentAPIVersion, apiVersion)
+	var invalid *ValidationError
+	assert.True(t, errors.As(err, &invalid))
+	assert.Equal(t, []Problem{
+		{Line: 4, Column: 1, Key: "developmet", Message: "unknown key; did you mean development?"},
+		{Line: 7, Column: 12, Key: "development.enabled", Message: `must be true or false, not "yes please"`},
+		{Line: 10, Column: 13, Key: "development.builds.broken", Message: "must be a string, not a list"},
+		{Line: 12, Column: 9, Key: "autoPrune.keep", Message: `must be an integer, not "three"`},
+		{Line: 15, Column: 12, Key: "hooks.postUse", Message: `must be a list, not "echo done"`},
+		{Line: 18, Column: 7, Key: "hooks.preUse[1]", Message: `must be a string, not "1"`},
+		{Line: 19, Column: 3, Key: "hooks.onFailure", Message: "unknown key; expected one of postInstall, postUse, preInstall, preUse"},
+	}, invalid.Problems)
+	assert.Contains(t, err.Error(), "invalid config file "+path+":\n  line 4: developmet: unknown key; did you mean development?\n")
+
+	assert.NoError(t, os.WriteFile(path, []byte("apiVersion: educatesenv/v9\n"), 0o644))
+	_, err = ValidateFile(path)
+	assert.ErrorContains(t, err, `line 1: apiVersion: "educatesenv/v9" is not supported`)
+
+	assert.NoError(t, os.WriteFile(path, []byte("github:\n  org: [\n"), 0o644))
+	_, err = ValidateFile(path)
+	assert.ErrorContains(t, err, "failed to parse config file")
+
+	// Empty files and files written for earlier versions are valid
+	assert.NoError(t, os.WriteFile(path, nil, 0o644))
+	_, err = ValidateFile(path)
+	assert.NoError(t, err)
+
+	assert.NoError(t, os.WriteFile(path, []byte("GitHub:\n  org: educates\nhooks:\n  postUse: echo done\n"), 0o644))
+	apiVersion, err = ValidateFile(path)
+	assert.NoError(t, err)
+	assert.Empty(t, apiVersion)
+}
+
+func TestJSONSchema(t *testing.T) {
+	data, err := JSONSchema()
+	assert.NoError(t, err)
+
+	var schema map[string]any
+	assert.NoError(t, json.Unmarshal(data, &schema))
+	assert.Equal(t, "object", schema["type"])
+	assert.Equal(t, false, schema["additionalProperties"])
+
+	properties := schema["properties"].(map[string]any)
+	assert.Equal(t, []any{CurrentAPIVersion}, properties["apiVersion"].(map[string]any)["enum"])
+	development := properties["development"].(map[string]any)
+	builds := development["properties"].(map[string]any)["builds"].(map[string]any)
+	assert.Equal(t, "object", builds["type"])
+	assert.Equal(t, map[string]any{"type": "string"}, builds["additionalProperties"])
+	hooks := properties["hooks"].(map[string]any)["properties"].(map[string]any)
+	assert.Equal(t, "array", hooks["postUse"].(map[string]any)["type"])
+	assert.NotEmpty(t, hooks["postUse"].(map[string]any)["description"])
+	keep := properties["autoPrune"].(map[string]any)["properties"].(map[string]any)["keep"].(map[string]any)
+	assert.Equal(t, "integer", keep["type"])
+
+	// Every setting is described
+	var described []string
+	for key := range descriptions {
+		described = append(described, key)
+	}
+	assert.ElementsMatch(t, described, settingKeys(properties, ""))
+}
+
+// settingKeys returns the keys of the settings in the properties of a JSON Schema
+func settingKeys(properties map[string]any, parent string) []string {
+	var keys []string
+	for name, property := range properties {
+		key := joinKey(parent, name)
+		keys = append(keys, key)
+		if nested, ok := property.(map[string]any)["properties"].(map[string]any); ok {
+			keys = append(keys, settingKeys(nested, key)...)
+		}
+	}
+	return keys
+}
+
+func TestEditDistance(t *testing.T) {
+	assert.Equal(t, 0, editDistance("hooks", "hooks"))
+	assert.Equal(t, 1, editDistance("developmet", "development"))
+	assert.Equal(t, 2, editDistance("verfiy", "verify"))
+	assert.Equal(t, 5, editDistance("", "local"))
+}
@@ -0,0 +1,103 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/educates/educatesenv/pkg/paths"
+)
+
+// Trust records that the user trusts the project config file at path as it is now. A
+// project config file comes with a repository rather than from the user, and can change
+// the bin directory put on the PATH and the repository versions are installed from, so it
+// is only read once trusted. Trust covers the content of the file, so a change pulled into
+// the repository has to be trusted again.
+func Trust(path string) error {
+	return updateTrusted(path, true)
+}
+
+// Untrust removes the trust in the project config file at path
+func Untrust(path string) error {
+	return updateTrusted(path, false)
+}
+
+// IsTrusted reports whether the user trusts the project config file at path as it is now
+func IsTrusted(path string) (bool, error) {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return false, err
+	}
+	sum, err := fileDigest(path)
+	if err != nil {
+		return false, err
+	}
+	trusted, err := readTrusted(paths.Resolve().TrustedProjectsFile())
+	if err != nil {
+		return false, err
+	}
+	return trusted[path] == sum, nil
+}
+
+// updateTrusted adds the project config file at path to the trusted ones, or removes it
+func updateTrusted(path string, trust bool) error {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+	file := paths.Resolve().TrustedProjectsFile()
+	trusted, err := readTrusted(file)
+	if err != nil {
+		return err
+	}
+	if trust {
+		sum, err := fileDigest(path)
+		if err != nil {
+			return err
+		}
+		trusted[path] = sum
+	} else {
+		delete(trusted, path)
+	}
+
+	data, err := json.MarshalIndent(trusted, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode trusted project config files: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+		return fmt.Errorf("failed to create directory for %s: %w", file, err)
+	}
+	if err := os.WriteFile(file, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", file, err)
+	}
+	return nil
+}
+
+// readTrusted reads the SHA-256 digests of the trusted project config files, by path
+func readTrusted(file string) (map[string]string, error) {
+	trusted := map[string]string{}
+	data, err := os.ReadFile(file)
+	if os.IsNotExist(err) {
+		return trusted, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", file, err)
+	}
+	if err := json.Unmarshal(data, &trusted); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", file, err)
+	}
+	return trusted, nil
+}
+
+// fileDigest returns the SHA-256 digest of the file at path, hex encoded
+func fileDigest(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,54 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/educates/educatesenv/pkg/paths"
+)
+
+func TestTrust(t *testing.T) {
+	dir := setupConfigDir(t)
+	project := filepath.Join(dir, "workshop")
+	path := filepath.Join(project, paths.ProjectConfigName)
+	writeFile(t, path, "local:\n  dir: bin\ngithub:\n  org: someone-else\n")
+	t.Chdir(project)
+
+	// An untrusted project config file cannot change the bin directory or the repository
+	c := New()
+	assert.NoError(t, c.Load())
+	assert.Equal(t, paths.Resolve().BinDir(), c.Local.Dir)
+	assert.Equal(t, DefaultGithubOrg, c.Github.Org)
+	assert.Empty(t, c.Files())
+	assert.Equal(t, []string{"ignoring " + path + ", which is not trusted. Review it and run `educatesenv config trust` to use it"}, c.Warnings())
+
+	assert.NoError(t, Trust(path))
+	trusted, err := IsTrusted(path)
+	assert.NoError(t, err)
+	assert.True(t, trusted)
+	c = New()
+	assert.NoError(t, c.Load())
+	assert.Equal(t, filepath.Join(project, "bin"), c.Local.Dir)
+	assert.Equal(t, "someone-else", c.Github.Org)
+	assert.Empty(t, c.Warnings())
+
+	// A change to the file has to be trusted again
+	writeFile(t, path, "local:\n  dir: other\n")
+	c = New()
+	assert.NoError(t, c.Load())
+	assert.Equal(t, paths.Resolve().BinDir(), c.Local.Dir)
+	assert.Len(t, c.Warnings(), 1)
+
+	assert.NoError(t, Trust(path))
+	assert.NoError(t, Untrust(path))
+	trusted, err = IsTrusted(path)
+	assert.NoError(t, err)
+	assert.False(t, trusted)
+
+	info, err := os.Stat(paths.Resolve().TrustedProjectsFile())
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+}
@@ -0,0 +1,96 @@
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// CachedRelease is the subset of a GitHub release kept in the local release cache
+type CachedRelease struct {
+	Tag        string `json:"tag"`
+	Prerelease bool   `json:"prerelease"`
+}
+
+// releaseCache is the on-disk format of the local release cache
+type releaseCache struct {
+	Org        string          `json:"org"`
+	Repository string          `json:"repository"`
+	BaseURL    string          `json:"baseURL,omitempty"`
+	FetchedAt  time.Time       `json:"fetchedAt"`
+	Releases   []CachedRelease `json:"releases"`
+}
+
+// ListReleasesCached returns the repository's releases from cacheFile if it was written for
+// the configured repository less than ttl ago. Otherwise releases are fetched from GitHub and
+// the cache is refreshed. If GitHub cannot be reached, a stale cache is returned rather than
+// an error.
+func (c *Client) ListReleasesCached(cacheFile string, ttl time.Duration) ([]CachedRelease, error) {
+	cache, cacheErr := c.readReleaseCache(cacheFile)
+	if cacheErr == nil && time.Since(cache.FetchedAt) < ttl {
+		return cache.Releases, nil
+	}
+
+	releases, err := c.ListReleases()
+	if err != nil {
+		if cacheErr == nil {
+			return cache.Releases, nil
+		}
+		return nil, err
+	}
+
+	fresh := releaseCache{
+		Org:        c.config.Github.Org,
+		Repository: c.config.Github.Repository,
+		BaseURL:    c.config.Github.BaseURL,
+		FetchedAt:  time.Now(),
+	}
+	for _, rel := range releases {
+		fresh.Releases = append(fresh.Releases, CachedRelease{
+			Tag:        rel.GetTagName(),
+			Prerelease: rel.GetPrerelease(),
+		})
+	}
+	// The cache is only an optimization, so failing to write it is not an error
+	_ = writeReleaseCache(cacheFile, &fresh)
+
+	return fresh.Releases, nil
+}
+
+// readReleaseCache reads the cache file, rejecting caches written for another repository
+func (c *Client) readReleaseCache(cacheFile string) (*releaseCache, error) {
+	data, err := os.ReadFile(cacheFile)
+	if err != nil {
+		return nil, err
+	}
+	var cache releaseCache
+	if err := json.Unmarshal(data, &cache); err != nil {
+		return nil, fmt.Errorf("failed to parse release cache %s: %w", cacheFile, err)
+	}
+	if cache.Org != c.config.Github.Org || cache.Repository != c.config.Github.Repository || cache.BaseURL != c.config.Github.BaseURL {
+		return nil, fmt.Errorf("release cache %s belongs to %s/%s at %s", cacheFile, cache.Org, cache.Repository, cache.host())
+	}
+	return &cache, nil
+}
+
+// host returns the GitHub server the cache was written for
+func (r *releaseCache) host() string {
+	if r.BaseURL == "" {
+		return "github.com"
+	}
+	return r.BaseURL
+}
+
+// writeReleaseCache writes the cache file, creating its directory if needed
+func writeReleaseCache(cacheFile string, cache *releaseCache) error {
+	data, err := json.Marshal(cache)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(cacheFile), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(cacheFile, data, 0o644)
+}
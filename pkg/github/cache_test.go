@@ -0,0 +1,52 @@
+package github
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/educates/educatesenv/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListReleasesCached(t *testing.T) {
+	cfg := config.New()
+	cfg.Github.Org = "testorg"
+	cfg.Github.Repository = "testrepo"
+	client := New(cfg)
+
+	cacheFile := filepath.Join(t.TempDir(), "cache", "releases.json")
+	releases := []CachedRelease{{Tag: "3.2.1"}, {Tag: "3.3.0-rc.1", Prerelease: true}}
+	err := writeReleaseCache(cacheFile, &releaseCache{
+		Org:        "testorg",
+		Repository: "testrepo",
+		FetchedAt:  time.Now(),
+		Releases:   releases,
+	})
+	assert.NoError(t, err)
+
+	// A fresh cache is served without contacting GitHub
+	result, err := client.ListReleasesCached(cacheFile, time.Hour)
+	assert.NoError(t, err)
+	assert.Equal(t, releases, result)
+
+	// A cache written for another repository is not used
+	cfg.Github.Repository = "otherrepo"
+	_, err = client.readReleaseCache(cacheFile)
+	assert.Error(t, err)
+
+	// Or for the same repository on another server
+	cfg.Github.Repository = "testrepo"
+	cfg.Github.BaseURL = "https://github.example.com/api/v3/"
+	_, err = client.readReleaseCache(cacheFile)
+	assert.ErrorContains(t, err, "at github.com")
+}
+
+func TestNewEnterprise(t *testing.T) {
+	cfg := config.New()
+	cfg.Github.BaseURL = "https://github.example.com"
+	client := New(cfg)
+	assert.Equal(t, "https://github.example.com/api/v3/", client.client.BaseURL.String())
+
+	assert.Equal(t, "https://api.github.com/", New(config.New()).client.BaseURL.String())
+}
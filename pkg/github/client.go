@@ -3,19 +3,25 @@ package github
 import (
 	"context"
 	"fmt"
+	"io"
+	"net/http"
 
 	"github.com/educates/educatesenv/pkg/config"
 	"github.com/google/go-github/v71/github"
 	"golang.org/x/oauth2"
 )
 
+// maxSmallAssetSize limits the size of assets downloaded into memory
+const maxSmallAssetSize = 1 << 20
+
 // Client wraps the GitHub client with our configuration
 type Client struct {
 	client *github.Client
 	config *config.Config
 }
 
-// New creates a new GitHub client with optional authentication
+// New creates a new GitHub client with optional authentication, for github.com or the
+// GitHub Enterprise Server at github.baseURL
 func New(cfg *config.Config) *Client {
 	ctx := context.Background()
 	var client *github.Client
@@ -26,6 +32,12 @@ func New(cfg *config.Config) *Client {
 	} else {
 		client = github.NewClient(nil)
 	}
+	// The URL is checked when the configuration is loaded
+	if cfg.Github.BaseURL != "" {
+		if enterprise, err := client.WithEnterpriseURLs(cfg.Github.BaseURL, cfg.Github.BaseURL); err == nil {
+			client = enterprise
+		}
+	}
 
 	return &Client{
 		client: client,
@@ -33,9 +45,9 @@ func New(cfg *config.Config) *Client {
 	}
 }
 
-// GetLatestReleaseVersion returns the latest stable release version
-func (c *Client) GetLatestReleaseVersion() (string, error) {
-	releases, resp, err := c.client.Repositories.ListReleases(context.Background(), c.config.Github.Org, c.config.Github.Repository, &github.ListOptions{PerPage: 10})
+// GetLatestReleaseVersion returns the latest stable release version, giving up when ctx is done
+func (c *Client) GetLatestReleaseVersion(ctx context.Context) (string, error) {
+	releases, resp, err := c.client.Repositories.ListReleases(ctx, c.config.Github.Org, c.config.Github.Repository, &github.ListOptions{PerPage: 10})
 	if err != nil {
 		if resp != nil && resp.StatusCode == 404 {
 			return "", fmt.Errorf("repository %s/%s not found", c.config.Github.Org, c.config.Github.Repository)
@@ -55,22 +67,91 @@ func (c *Client) GetLatestReleaseVersion() (string, error) {
 	return "", fmt.Errorf("no stable releases found in %s/%s. Try 'educatesenv list-remote --all' to see pre-releases", c.config.Github.Org, c.config.Github.Repository)
 }
 
-// GetReleaseAssetURL gets the download URL for a specific version and platform
-func (c *Client) GetReleaseAssetURL(version, assetName string) (string, error) {
+// GetReleaseAssets returns the API URLs of all assets of a release, keyed by asset name.
+// Download them with OpenAsset.
+func (c *Client) GetReleaseAssets(version string) (map[string]string, error) {
 	release, resp, err := c.client.Repositories.GetReleaseByTag(context.Background(), c.config.Github.Org, c.config.Github.Repository, version)
 	if err != nil {
 		if resp != nil && resp.StatusCode == 404 {
-			return "", fmt.Errorf("version %s not found. Run 'educatesenv list-remote' to see available versions", version)
+			return nil, fmt.Errorf("version %s not found. Run 'educatesenv list-remote' to see available versions", version)
 		}
-		return "", fmt.Errorf("failed to fetch release info: %w", err)
+		return nil, fmt.Errorf("failed to fetch release info: %w", err)
 	}
 
+	assets := make(map[string]string, len(release.Assets))
 	for _, a := range release.Assets {
-		if a.GetName() == assetName {
-			return a.GetBrowserDownloadURL(), nil
+		assets[a.GetName()] = a.GetURL()
+	}
+	return assets, nil
+}
+
+// ListReleaseAssets returns the API URLs of the assets of the releases, keyed by release
+// tag and asset name, from a single listing of the releases. Releases beyond the listing
+// are left out.
+func (c *Client) ListReleaseAssets() (map[string]map[string]string, error) {
+	releases, err := c.ListReleases()
+	if err != nil {
+		return nil, err
+	}
+	byTag := make(map[string]map[string]string, len(releases))
+	for _, release := range releases {
+		assets := make(map[string]string, len(release.Assets))
+		for _, a := range release.Assets {
+			assets[a.GetName()] = a.GetURL()
+		}
+		byTag[release.GetTagName()] = assets
+	}
+	return byTag, nil
+}
+
+// OpenAsset starts downloading the release asset at url, returned by GetReleaseAssets,
+// through the GitHub API with the configured token, so that assets of private
+// repositories and GitHub Enterprise Server can be downloaded. The caller must close the
+// returned body.
+func (c *Client) OpenAsset(url string) (io.ReadCloser, error) {
+	req, err := c.client.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", url, err)
+	}
+	req.Header.Set("Accept", "application/octet-stream")
+
+	// The API redirects to the storage the asset is served from, which rejects the token,
+	// so the redirect is followed without it
+	hc := *c.client.Client()
+	hc.CheckRedirect = func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", url, err)
+	}
+	if location := resp.Header.Get("Location"); location != "" && resp.StatusCode >= 300 && resp.StatusCode < 400 {
+		_ = resp.Body.Close()
+		if resp, err = http.Get(location); err != nil {
+			return nil, fmt.Errorf("failed to download %s: %w", url, err)
 		}
 	}
-	return "", fmt.Errorf("binary for %s is not available for your platform (%s). Please check supported platforms in the documentation", version, assetName)
+	if resp.StatusCode != http.StatusOK {
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("failed to download %s: %s", url, resp.Status)
+	}
+	return resp.Body, nil
+}
+
+// DownloadAsset downloads a small release asset, such as a checksum or signature file, into memory
+func (c *Client) DownloadAsset(url string) ([]byte, error) {
+	body, err := c.OpenAsset(url)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = body.Close() }()
+
+	data, err := io.ReadAll(io.LimitReader(body, maxSmallAssetSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", url, err)
+	}
+	if len(data) > maxSmallAssetSize {
+		return nil, fmt.Errorf("asset %s is larger than %d bytes", url, maxSmallAssetSize)
+	}
+	return data, nil
 }
 
 // ListReleases returns all releases from the repository
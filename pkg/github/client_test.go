@@ -0,0 +1,65 @@
+package github
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/educates/educatesenv/pkg/config"
+)
+
+func TestOpenAsset(t *testing.T) {
+	storage := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Storage rejects requests that carry the token
+		if r.Header.Get("Authorization") != "" {
+			http.Error(w, "only one auth mechanism allowed", http.StatusBadRequest)
+			return
+		}
+		_, _ = w.Write([]byte("binary"))
+	}))
+	defer storage.Close()
+
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" || r.Header.Get("Accept") != "application/octet-stream" {
+			http.NotFound(w, r)
+			return
+		}
+		switch r.URL.Path {
+		case "/api/v3/repos/org/repo/releases/assets/1":
+			http.Redirect(w, r, storage.URL+"/signed", http.StatusFound)
+		case "/api/v3/repos/org/repo/releases/assets/2":
+			_, _ = w.Write([]byte("checksums"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer api.Close()
+
+	cfg := config.New()
+	cfg.Github.BaseURL = api.URL
+	cfg.Github.Token = "secret"
+	client := New(cfg)
+
+	// Assets are requested from the API with the token, and redirects followed without it
+	body, err := client.OpenAsset(api.URL + "/api/v3/repos/org/repo/releases/assets/1")
+	assert.NoError(t, err)
+	data, err := io.ReadAll(body)
+	assert.NoError(t, err)
+	assert.NoError(t, body.Close())
+	assert.Equal(t, "binary", string(data))
+
+	data, err = client.DownloadAsset(api.URL + "/api/v3/repos/org/repo/releases/assets/2")
+	assert.NoError(t, err)
+	assert.Equal(t, "checksums", string(data))
+
+	_, err = client.OpenAsset(api.URL + "/api/v3/repos/org/repo/releases/assets/3")
+	assert.ErrorContains(t, err, "404 Not Found")
+
+	// Without the token, private assets cannot be downloaded
+	cfg.Github.Token = ""
+	_, err = New(cfg).OpenAsset(api.URL + "/api/v3/repos/org/repo/releases/assets/1")
+	assert.Error(t, err)
+}
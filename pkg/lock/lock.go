@@ -0,0 +1,93 @@
+package lock
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// pollInterval is how often Acquire retries while another process holds the lock
+const pollInterval = 100 * time.Millisecond
+
+// errLocked is returned by tryLock when another process holds the lock
+var errLocked = errors.New("lock is held by another process")
+
+// Lock is an advisory, exclusive lock on a file shared by all educatesenv processes
+type Lock struct {
+	file *os.File
+}
+
+// Acquire takes the lock at path, creating the file if needed. If another process holds
+// the lock, Acquire calls onWait once with that process's pid and keeps retrying until
+// timeout has elapsed.
+func Acquire(path string, timeout time.Duration, onWait func(pid int)) (*Lock, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create lock directory: %w", err)
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open lock file %s: %w", path, err)
+	}
+
+	deadline := time.Now().Add(timeout)
+	waited := false
+	for {
+		err := tryLock(file)
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, errLocked) {
+			_ = file.Close()
+			return nil, fmt.Errorf("failed to lock %s: %w", path, err)
+		}
+		if time.Now().After(deadline) {
+			_ = file.Close()
+			return nil, fmt.Errorf("another educatesenv is running (pid %s); gave up waiting after %s", holder(path), timeout)
+		}
+		if !waited && onWait != nil {
+			if pid, err := strconv.Atoi(holder(path)); err == nil {
+				onWait(pid)
+			} else {
+				onWait(0)
+			}
+		}
+		waited = true
+		time.Sleep(pollInterval)
+	}
+
+	// Record the owner so that waiting processes can report who holds the lock
+	if err := file.Truncate(0); err == nil {
+		_, _ = file.WriteAt([]byte(strconv.Itoa(os.Getpid())+"\n"), 0)
+	}
+	return &Lock{file: file}, nil
+}
+
+// Release releases the lock
+func (l *Lock) Release() error {
+	if err := unlock(l.file); err != nil {
+		_ = l.file.Close()
+		return fmt.Errorf("failed to release lock: %w", err)
+	}
+	return l.file.Close()
+}
+
+// holder returns the pid recorded in the lock file, or "unknown"
+func holder(path string) string {
+	file, err := os.Open(path)
+	if err != nil {
+		return "unknown"
+	}
+	defer func() { _ = file.Close() }()
+
+	data, err := io.ReadAll(io.LimitReader(file, 32))
+	pid := strings.TrimSpace(string(data))
+	if err != nil || pid == "" {
+		return "unknown"
+	}
+	return pid
+}
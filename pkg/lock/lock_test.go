@@ -0,0 +1,52 @@
+package lock
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAcquire(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", "educatesenv.lock")
+
+	first, err := Acquire(path, time.Second, nil)
+	assert.NoError(t, err)
+
+	// The lock file records the holder's pid
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, strconv.Itoa(os.Getpid())+"\n", string(data))
+
+	// A second lock times out and reports the holder
+	waitedFor := -1
+	_, err = Acquire(path, 3*pollInterval, func(pid int) { waitedFor = pid })
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "another educatesenv is running (pid "+strconv.Itoa(os.Getpid())+")")
+	assert.Equal(t, os.Getpid(), waitedFor)
+
+	// Once released, the lock can be taken again
+	assert.NoError(t, first.Release())
+	second, err := Acquire(path, time.Second, nil)
+	assert.NoError(t, err)
+	assert.NoError(t, second.Release())
+}
+
+func TestAcquireWaitsForRelease(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "educatesenv.lock")
+
+	first, err := Acquire(path, time.Second, nil)
+	assert.NoError(t, err)
+
+	go func() {
+		time.Sleep(3 * pollInterval)
+		assert.NoError(t, first.Release())
+	}()
+
+	second, err := Acquire(path, 5*time.Second, nil)
+	assert.NoError(t, err)
+	assert.NoError(t, second.Release())
+}
@@ -0,0 +1,31 @@
+//go:build windows
+
+package lock
+
+import (
+	"errors"
+	"math"
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// lockOffset is the start of the locked byte range. It lies beyond the pid written at the
+// start of the file, which would otherwise be unreadable to waiting processes.
+const lockOffset = math.MaxUint32
+
+// tryLock takes an exclusive lock on file without blocking
+func tryLock(file *os.File) error {
+	ol := &windows.Overlapped{Offset: lockOffset}
+	err := windows.LockFileEx(windows.Handle(file.Fd()), windows.LOCKFILE_EXCLUSIVE_LOCK|windows.LOCKFILE_FAIL_IMMEDIATELY, 0, 1, 0, ol)
+	if errors.Is(err, windows.ERROR_LOCK_VIOLATION) {
+		return errLocked
+	}
+	return err
+}
+
+// unlock releases the lock on file
+func unlock(file *os.File) error {
+	ol := &windows.Overlapped{Offset: lockOffset}
+	return windows.UnlockFileEx(windows.Handle(file.Fd()), 0, 1, 0, ol)
+}
@@ -0,0 +1,95 @@
+// Package lockfile reads and writes project lockfiles, which pin the educates release a
+// project uses down to the digest of the binary for each platform.
+package lockfile
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FileName is the name of the lockfile in a project directory
+const FileName = ".educates-version.lock"
+
+// header is written at the top of every lockfile
+const header = "# Generated by `educatesenv lock`. Do not edit; run `educatesenv lock <version>` to update.\n"
+
+// Lockfile pins a release and the digests of its binaries
+type Lockfile struct {
+	// Version is the release tag
+	Version string `yaml:"version"`
+	// Source is the GitHub repository the release is published in, as org/repository
+	Source string `yaml:"source"`
+	// Assets are the release binaries, keyed by os/arch
+	Assets map[string]Asset `yaml:"assets"`
+}
+
+// Asset is a pinned release binary
+type Asset struct {
+	Name   string `yaml:"name"`
+	SHA256 string `yaml:"sha256"`
+}
+
+// Key returns the key of the asset for an OS and architecture
+func Key(os, arch string) string {
+	return os + "/" + arch
+}
+
+// Asset returns the pinned binary for an OS and architecture
+func (l *Lockfile) Asset(os, arch string) (Asset, bool) {
+	asset, ok := l.Assets[Key(os, arch)]
+	return asset, ok
+}
+
+// Find returns the lockfile in dir or the closest of its parents that has one
+func Find(dir string) (string, bool) {
+	for {
+		path := filepath.Join(dir, FileName)
+		if fi, err := os.Stat(path); err == nil && !fi.IsDir() {
+			return path, true
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false
+		}
+		dir = parent
+	}
+}
+
+// Load reads and validates a lockfile
+func Load(path string) (*Lockfile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read lockfile: %w", err)
+	}
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	var l Lockfile
+	if err := dec.Decode(&l); err != nil {
+		return nil, fmt.Errorf("failed to parse lockfile %s: %w", path, err)
+	}
+	if l.Version == "" {
+		return nil, fmt.Errorf("lockfile %s does not name a version", path)
+	}
+	for key, asset := range l.Assets {
+		if asset.Name == "" || len(asset.SHA256) != 64 {
+			return nil, fmt.Errorf("lockfile %s has an invalid entry for %s", path, key)
+		}
+	}
+	return &l, nil
+}
+
+// Save writes the lockfile to path
+func (l *Lockfile) Save(path string) error {
+	data, err := yaml.Marshal(l)
+	if err != nil {
+		return fmt.Errorf("failed to marshal lockfile: %w", err)
+	}
+	if err := os.WriteFile(path, append([]byte(header), data...), 0o644); err != nil {
+		return fmt.Errorf("failed to write lockfile: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package lockfile
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+	l := &Lockfile{
+		Version: "3.2.1",
+		Source:  "educates/educates-training-platform",
+		Assets: map[string]Asset{
+			Key("linux", "amd64"): {Name: "educates-linux-amd64", SHA256: strings.Repeat("a", 64)},
+		},
+	}
+	assert.NoError(t, l.Save(path))
+
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(data), "# Generated by `educatesenv lock`"))
+
+	loaded, err := Load(path)
+	assert.NoError(t, err)
+	assert.Equal(t, l, loaded)
+
+	asset, ok := loaded.Asset("linux", "amd64")
+	assert.True(t, ok)
+	assert.Equal(t, "educates-linux-amd64", asset.Name)
+	_, ok = loaded.Asset("windows", "amd64")
+	assert.False(t, ok)
+}
+
+func TestLoadInvalid(t *testing.T) {
+	tests := map[string]string{
+		"no version":   "assets: {}\n",
+		"short digest": "version: 3.2.1\nassets:\n  linux/amd64:\n    name: educates-linux-amd64\n    sha256: abc\n",
+		"unknown key":  "version: 3.2.1\ndigest: abc\n",
+	}
+	for name, content := range tests {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), FileName)
+			assert.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+			_, err := Load(path)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestFind(t *testing.T) {
+	root := t.TempDir()
+	nested := filepath.Join(root, "a", "b")
+	assert.NoError(t, os.MkdirAll(nested, 0o755))
+
+	_, found := Find(nested)
+	assert.False(t, found)
+
+	assert.NoError(t, os.WriteFile(filepath.Join(root, "a", FileName), []byte("version: 3.2.1\n"), 0o644))
+	path, found := Find(nested)
+	assert.True(t, found)
+	assert.Equal(t, filepath.Join(root, "a", FileName), path)
+}
@@ -0,0 +1,139 @@
+// Package manifest reads team manifests, which list the educates versions a machine must
+// have, and compares them with what is installed.
+package manifest
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"slices"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/educates/educatesenv/pkg/semver"
+)
+
+// Manifest lists the versions a machine must have installed
+type Manifest struct {
+	// Versions are the exact versions that must be installed
+	Versions []string `yaml:"versions"`
+	// Default is the version that must be active. It is installed even if not listed.
+	Default string `yaml:"default"`
+	// Source is the repository releases must be installed from, if set
+	Source *Source `yaml:"source"`
+}
+
+// Source identifies the GitHub repository releases are installed from
+type Source struct {
+	Org        string `yaml:"org"`
+	Repository string `yaml:"repository"`
+}
+
+// String returns the source as org/repository
+func (s Source) String() string {
+	return s.Org + "/" + s.Repository
+}
+
+// Load reads and validates a manifest. Unknown fields are rejected, so that a misspelt
+// setting is not silently ignored.
+func Load(path string) (*Manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	var m Manifest
+	if err := dec.Decode(&m); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest %s: %w", path, err)
+	}
+	if err := m.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid manifest %s: %w", path, err)
+	}
+	return &m, nil
+}
+
+// Validate checks that the manifest names exact versions
+func (m *Manifest) Validate() error {
+	if len(m.Versions) == 0 && m.Default == "" {
+		return fmt.Errorf("no versions listed")
+	}
+	seen := map[string]bool{}
+	for _, v := range m.Versions {
+		if _, err := semver.Parse(v); err != nil {
+			return fmt.Errorf("versions: %q is not an exact version", v)
+		}
+		if seen[v] {
+			return fmt.Errorf("versions: %s is listed more than once", v)
+		}
+		seen[v] = true
+	}
+	if m.Default != "" {
+		if _, err := semver.Parse(m.Default); err != nil {
+			return fmt.Errorf("default: %q is not an exact version", m.Default)
+		}
+	}
+	if m.Source != nil && (m.Source.Org == "" || m.Source.Repository == "") {
+		return fmt.Errorf("source: both org and repository must be set")
+	}
+	return nil
+}
+
+// Required returns the versions that must be installed, including the default
+func (m *Manifest) Required() []string {
+	required := slices.Clone(m.Versions)
+	if m.Default != "" && !slices.Contains(required, m.Default) {
+		required = append(required, m.Default)
+	}
+	semver.Sort(required)
+	return required
+}
+
+// State describes what is installed on a machine
+type State struct {
+	Installed []string
+	Active    string
+	Source    Source
+}
+
+// Drift describes how a machine differs from a manifest
+type Drift struct {
+	// Missing are required versions that are not installed
+	Missing []string
+	// Unlisted are installed versions the manifest does not list
+	Unlisted []string
+	// Default is the version that must be made active, or empty if it already is
+	Default string
+	// Source is the source that must be configured, or nil if it already is
+	Source *Source
+}
+
+// Compare returns how state differs from the manifest
+func Compare(m *Manifest, state State) Drift {
+	var drift Drift
+	required := m.Required()
+	for _, v := range required {
+		if !slices.Contains(state.Installed, v) {
+			drift.Missing = append(drift.Missing, v)
+		}
+	}
+	for _, v := range state.Installed {
+		if !slices.Contains(required, v) {
+			drift.Unlisted = append(drift.Unlisted, v)
+		}
+	}
+	semver.Sort(drift.Unlisted)
+	if m.Default != "" && state.Active != m.Default {
+		drift.Default = m.Default
+	}
+	if m.Source != nil && *m.Source != state.Source {
+		source := *m.Source
+		drift.Source = &source
+	}
+	return drift
+}
+
+// InSync reports whether there is no drift. Unlisted versions only count when prune is set.
+func (d Drift) InSync(prune bool) bool {
+	return len(d.Missing) == 0 && d.Default == "" && d.Source == nil && (!prune || len(d.Unlisted) == 0)
+}
@@ -0,0 +1,79 @@
+package manifest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeManifest(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "manifest.yaml")
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	m, err := Load(writeManifest(t, `
+versions:
+  - 3.2.3
+  - 3.1.4
+default: 3.3.0
+source:
+  org: educates
+  repository: educates-training-platform
+`))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"3.2.3", "3.1.4"}, m.Versions)
+	assert.Equal(t, "3.3.0", m.Default)
+	assert.Equal(t, "educates/educates-training-platform", m.Source.String())
+	assert.Equal(t, []string{"3.1.4", "3.2.3", "3.3.0"}, m.Required())
+
+	tests := map[string]string{
+		"unknown field":     "versions: [3.2.3]\ndefualt: 3.2.3\n",
+		"constraint":        "versions: [~3.2]\n",
+		"duplicate":         "versions: [3.2.3, 3.2.3]\n",
+		"bad default":       "versions: [3.2.3]\ndefault: stable\n",
+		"empty":             "source:\n  org: educates\n  repository: x\n",
+		"incomplete source": "versions: [3.2.3]\nsource:\n  org: educates\n",
+	}
+	for name, content := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := Load(writeManifest(t, content))
+			assert.Error(t, err)
+		})
+	}
+
+	_, err = Load(filepath.Join(t.TempDir(), "missing.yaml"))
+	assert.Error(t, err)
+}
+
+func TestCompare(t *testing.T) {
+	m := &Manifest{
+		Versions: []string{"3.1.4", "3.2.3"},
+		Default:  "3.2.3",
+		Source:   &Source{Org: "educates", Repository: "educates-training-platform"},
+	}
+
+	drift := Compare(m, State{
+		Installed: []string{"3.0.0", "3.2.3", "3.10.0"},
+		Active:    "3.0.0",
+		Source:    Source{Org: "fork", Repository: "educates-training-platform"},
+	})
+	assert.Equal(t, []string{"3.1.4"}, drift.Missing)
+	assert.Equal(t, []string{"3.0.0", "3.10.0"}, drift.Unlisted)
+	assert.Equal(t, "3.2.3", drift.Default)
+	assert.Equal(t, m.Source, drift.Source)
+	assert.False(t, drift.InSync(false))
+
+	// Unlisted versions are only drift when pruning
+	drift = Compare(m, State{
+		Installed: []string{"3.0.0", "3.1.4", "3.2.3"},
+		Active:    "3.2.3",
+		Source:    *m.Source,
+	})
+	assert.Equal(t, Drift{Unlisted: []string{"3.0.0"}}, drift)
+	assert.True(t, drift.InSync(false))
+	assert.False(t, drift.InSync(true))
+}
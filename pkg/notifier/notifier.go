@@ -0,0 +1,120 @@
+// Package notifier tells users when a newer educates release is available, checking for
+// one at most once per interval.
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/educates/educatesenv/pkg/semver"
+)
+
+const (
+	// DisableEnv is the environment variable that turns the update notifier off when set
+	DisableEnv = "EDUCATESENV_NO_UPDATE_NOTIFIER"
+	// DefaultTimeout is how long a check waits for GitHub by default
+	DefaultTimeout = 2 * time.Second
+)
+
+// state is the on-disk record of the last update check
+type state struct {
+	Repository string    `json:"repository"`
+	CheckedAt  time.Time `json:"checkedAt"`
+	Latest     string    `json:"latest,omitempty"`
+}
+
+// Notifier checks for the latest release, remembering the result in a state file so that
+// GitHub is contacted at most once per interval
+type Notifier struct {
+	// StatePath is the file the result of the last check is stored in
+	StatePath string
+	// Repository identifies the server and repository checked, so that changing either
+	// forces a new check
+	Repository string
+	// Interval is the minimum time between checks
+	Interval time.Duration
+	// Fetch returns the latest stable release, giving up when the context is done
+	Fetch func(ctx context.Context) (string, error)
+	// Timeout limits how long a check waits for Fetch, DefaultTimeout if zero, so that a
+	// slow or unreachable GitHub does not hold up the command that shows the notice
+	Timeout time.Duration
+	// Now returns the current time
+	Now func() time.Time
+}
+
+// Latest returns the latest stable release, fetching it only if the last check is older
+// than the interval. A failed check is recorded as well, so that an unreachable GitHub
+// is not retried on every command; the release found by the previous check is returned.
+func (n *Notifier) Latest() (string, error) {
+	now := time.Now()
+	if n.Now != nil {
+		now = n.Now()
+	}
+
+	previous, err := n.readState()
+	if err == nil && previous.Repository == n.Repository && now.Sub(previous.CheckedAt) < n.Interval {
+		return previous.Latest, nil
+	}
+
+	current := state{Repository: n.Repository, CheckedAt: now}
+	if previous != nil && previous.Repository == n.Repository {
+		current.Latest = previous.Latest
+	}
+	timeout := n.Timeout
+	if timeout == 0 {
+		timeout = DefaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	latest, fetchErr := n.Fetch(ctx)
+	if fetchErr == nil {
+		current.Latest = latest
+	}
+	if err := n.writeState(&current); err != nil {
+		return current.Latest, fmt.Errorf("failed to write update check state %s: %w", n.StatePath, err)
+	}
+	return current.Latest, fetchErr
+}
+
+// readState reads the state file
+func (n *Notifier) readState() (*state, error) {
+	data, err := os.ReadFile(n.StatePath)
+	if err != nil {
+		return nil, err
+	}
+	var s state
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// writeState writes the state file
+func (n *Notifier) writeState(s *state) error {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(n.StatePath), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(n.StatePath, data, 0o644)
+}
+
+// Message returns the notice shown when latest is newer than current, or an empty string
+// if it is not or either is not a semantic version
+func Message(current, latest string) string {
+	cv, err := semver.Parse(current)
+	if err != nil {
+		return ""
+	}
+	lv, err := semver.Parse(latest)
+	if err != nil || lv.Compare(cv) <= 0 {
+		return ""
+	}
+	return fmt.Sprintf("educates %s is available (you are on %s)", latest, current)
+}
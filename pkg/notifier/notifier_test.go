@@ -0,0 +1,101 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLatest(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	fetches := 0
+	latest, fetchErr := "3.3.0", error(nil)
+	n := &Notifier{
+		StatePath:  filepath.Join(t.TempDir(), "state", "update-check.json"),
+		Repository: "educates/educates-training-platform",
+		Interval:   24 * time.Hour,
+		Fetch: func(context.Context) (string, error) {
+			fetches++
+			return latest, fetchErr
+		},
+		Now: func() time.Time { return now },
+	}
+
+	// The first check fetches the latest release
+	result, err := n.Latest()
+	assert.NoError(t, err)
+	assert.Equal(t, "3.3.0", result)
+	assert.Equal(t, 1, fetches)
+
+	// Within the interval the stored result is used
+	latest = "3.4.0"
+	now = now.Add(time.Hour)
+	result, err = n.Latest()
+	assert.NoError(t, err)
+	assert.Equal(t, "3.3.0", result)
+	assert.Equal(t, 1, fetches)
+
+	// Once the interval has passed, GitHub is checked again
+	now = now.Add(24 * time.Hour)
+	result, err = n.Latest()
+	assert.NoError(t, err)
+	assert.Equal(t, "3.4.0", result)
+	assert.Equal(t, 2, fetches)
+
+	// A failed check keeps the previous result and is not retried within the interval
+	fetchErr = errors.New("offline")
+	now = now.Add(25 * time.Hour)
+	result, err = n.Latest()
+	assert.Error(t, err)
+	assert.Equal(t, "3.4.0", result)
+	result, err = n.Latest()
+	assert.NoError(t, err)
+	assert.Equal(t, "3.4.0", result)
+	assert.Equal(t, 3, fetches)
+
+	// Changing the repository forces a new check
+	fetchErr = nil
+	n.Repository = "example/fork"
+	latest = "1.0.0"
+	result, err = n.Latest()
+	assert.NoError(t, err)
+	assert.Equal(t, "1.0.0", result)
+	assert.Equal(t, 4, fetches)
+}
+
+func TestLatestTimeout(t *testing.T) {
+	n := &Notifier{
+		StatePath:  filepath.Join(t.TempDir(), "update-check.json"),
+		Repository: "educates/educates-training-platform",
+		Interval:   24 * time.Hour,
+		Timeout:    10 * time.Millisecond,
+		Fetch: func(ctx context.Context) (string, error) {
+			select {
+			case <-ctx.Done():
+				return "", ctx.Err()
+			case <-time.After(time.Minute):
+				return "3.3.0", nil
+			}
+		},
+	}
+
+	// An unresponsive GitHub is given up on, and not retried within the interval
+	start := time.Now()
+	_, err := n.Latest()
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 10*time.Second)
+	_, err = n.Latest()
+	assert.NoError(t, err)
+}
+
+func TestMessage(t *testing.T) {
+	assert.Equal(t, "educates 3.3.0 is available (you are on 3.2.1)", Message("3.2.1", "3.3.0"))
+	assert.Empty(t, Message("3.3.0", "3.3.0"))
+	assert.Empty(t, Message("3.4.0", "3.3.0"))
+	assert.Empty(t, Message("develop", "3.3.0"))
+	assert.Empty(t, Message("3.2.1", ""))
+}
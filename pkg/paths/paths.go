@@ -0,0 +1,224 @@
+// Package paths resolves where educatesenv keeps its configuration, installed binaries,
+// caches and state.
+//
+// When EDUCATESENV_ROOT is set, everything is kept in that one directory. When a file
+// named portable sits next to the educatesenv executable, everything is kept in the
+// executable's directory, so that the whole tree can be moved or mounted elsewhere. Otherwise the
+// XDG Base Directory layout is used, so that configuration, which is worth backing up, is
+// kept apart from caches, which are not. On Windows, where XDG is not a convention, the
+// legacy single directory ~/.educatesenv is used instead.
+package paths
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+const (
+	// RootEnv is the environment variable that puts everything in a single directory
+	RootEnv = "EDUCATESENV_ROOT"
+	// LegacyDirName is the name of the directory in the home directory that educatesenv used
+	// for everything before adopting the XDG layout
+	LegacyDirName = ".educatesenv"
+	// PortableMarker is the file that, next to the educatesenv executable, enables portable mode
+	PortableMarker = "portable"
+	// ProjectConfigName is the name of the project config file, which applies in the directory
+	// it is in and below
+	ProjectConfigName = ".educatesenv.yaml"
+	// appName is the name of the educatesenv directory within each XDG base directory
+	appName = "educatesenv"
+)
+
+// Layout holds the directories educatesenv uses
+type Layout struct {
+	// Config holds config.yaml and aliases.yaml
+	Config string
+	// Data holds installed versions, development builds and plugins
+	Data string
+	// Cache holds data fetched from GitHub, which can always be fetched again
+	Cache string
+	// State holds the lock file and update check results
+	State string
+	// Single is true if all directories are the same one
+	Single bool
+	// Portable is true if the layout is rooted next to the executable by a portable marker
+	Portable bool
+}
+
+// Resolve returns the layout for the current environment
+func Resolve() Layout {
+	return resolve(os.Getenv, runtime.GOOS, executable())
+}
+
+// resolve returns the layout for the environment read through getenv, for the
+// educatesenv executable at exe
+func resolve(getenv func(string) string, goos, exe string) Layout {
+	if root := getenv(RootEnv); root != "" {
+		return single(root)
+	}
+	if exe != "" {
+		dir := filepath.Dir(exe)
+		if fi, err := os.Stat(filepath.Join(dir, PortableMarker)); err == nil && !fi.IsDir() {
+			layout := single(dir)
+			layout.Portable = true
+			return layout
+		}
+	}
+	home := homeDir()
+	if goos == "windows" {
+		return single(filepath.Join(home, LegacyDirName))
+	}
+	return Layout{
+		Config: xdgDir(getenv, "XDG_CONFIG_HOME", home, ".config"),
+		Data:   xdgDir(getenv, "XDG_DATA_HOME", home, ".local", "share"),
+		Cache:  xdgDir(getenv, "XDG_CACHE_HOME", home, ".cache"),
+		State:  xdgDir(getenv, "XDG_STATE_HOME", home, ".local", "state"),
+	}
+}
+
+// single returns the layout that keeps everything in root
+func single(root string) Layout {
+	return Layout{Config: root, Data: root, Cache: filepath.Join(root, "cache"), State: root, Single: true}
+}
+
+// xdgDir returns the educatesenv directory within the XDG base directory named by env,
+// falling back to the default below home if it is unset or not absolute, as the
+// specification requires
+func xdgDir(getenv func(string) string, env, home string, fallback ...string) string {
+	base := getenv(env)
+	if base == "" || !filepath.IsAbs(base) {
+		base = filepath.Join(append([]string{home}, fallback...)...)
+	}
+	return filepath.Join(base, appName)
+}
+
+// executable returns the path of the running executable with symlinks resolved, or an
+// empty string if it cannot be determined
+func executable() string {
+	exe, err := os.Executable()
+	if err != nil {
+		return ""
+	}
+	if resolved, err := filepath.EvalSymlinks(exe); err == nil {
+		return resolved
+	}
+	return exe
+}
+
+// homeDir returns the home directory, or the current directory if it is unknown
+func homeDir() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "."
+	}
+	return home
+}
+
+// LegacyDir returns the directory educatesenv used for everything before adopting the
+// XDG layout
+func LegacyDir() string {
+	return filepath.Join(homeDir(), LegacyDirName)
+}
+
+// Abs returns path made absolute relative to the config directory if the layout is
+// portable and it is relative. Other paths are returned unchanged.
+func (l Layout) Abs(path string) string {
+	if !l.Portable || path == "" || filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(l.Config, path)
+}
+
+// Rel returns path relative to the config directory if the layout is portable and it is
+// inside the config directory, so that it stays valid when the tree is moved. Other paths
+// are returned unchanged.
+func (l Layout) Rel(path string) string {
+	if !l.Portable || !filepath.IsAbs(path) {
+		return path
+	}
+	rel, err := filepath.Rel(l.Config, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return path
+	}
+	return rel
+}
+
+// SystemConfigFile returns the system-wide config file, which holds defaults set by an
+// administrator for every user
+func SystemConfigFile() string {
+	return systemConfigFile(os.Getenv, runtime.GOOS)
+}
+
+// systemConfigFile returns the system-wide config file for the environment read through getenv
+func systemConfigFile(getenv func(string) string, goos string) string {
+	if goos == "windows" {
+		base := getenv("ProgramData")
+		if base == "" {
+			base = `C:\ProgramData`
+		}
+		return filepath.Join(base, appName, "config.yaml")
+	}
+	return filepath.Join("/etc", appName, "config.yaml")
+}
+
+// FindProjectConfig returns the nearest project config file in dir or one of its parents
+func FindProjectConfig(dir string) (string, bool) {
+	for {
+		path := filepath.Join(dir, ProjectConfigName)
+		if fi, err := os.Stat(path); err == nil && !fi.IsDir() {
+			return path, true
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false
+		}
+		dir = parent
+	}
+}
+
+// ConfigFile returns the user config file
+func (l Layout) ConfigFile() string {
+	return filepath.Join(l.Config, "config.yaml")
+}
+
+// AliasesFile returns the file that stores the version aliases
+func (l Layout) AliasesFile() string {
+	return filepath.Join(l.Config, "aliases.yaml")
+}
+
+// BinDir returns the default directory versions are installed in
+func (l Layout) BinDir() string {
+	return filepath.Join(l.Data, "bin")
+}
+
+// DevBuildsDir returns the directory `educatesenv dev build` stores the binaries it builds in
+func (l Layout) DevBuildsDir() string {
+	return filepath.Join(l.Data, "dev")
+}
+
+// PluginsDir returns the directory searched for plugins before the PATH
+func (l Layout) PluginsDir() string {
+	return filepath.Join(l.Data, "plugins")
+}
+
+// CacheDir returns the directory data fetched from GitHub is cached in
+func (l Layout) CacheDir() string {
+	return l.Cache
+}
+
+// LockFile returns the lock file that serializes changes made by concurrent processes
+func (l Layout) LockFile() string {
+	return filepath.Join(l.State, "educatesenv.lock")
+}
+
+// TrustedProjectsFile returns the file that records the project config files the user trusts
+func (l Layout) TrustedProjectsFile() string {
+	return filepath.Join(l.State, "trusted-projects.json")
+}
+
+// UpdateCheckFile returns the file that records the last check for a new educates release
+func (l Layout) UpdateCheckFile() string {
+	return filepath.Join(l.State, "update-check.json")
+}
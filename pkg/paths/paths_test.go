@@ -0,0 +1,123 @@
+package paths
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolve(t *testing.T) {
+	home, err := os.UserHomeDir()
+	assert.NoError(t, err)
+
+	env := map[string]string{}
+	getenv := func(key string) string { return env[key] }
+
+	// XDG defaults
+	layout := resolve(getenv, "linux", "")
+	assert.False(t, layout.Single)
+	assert.Equal(t, filepath.Join(home, ".config", "educatesenv", "config.yaml"), layout.ConfigFile())
+	assert.Equal(t, filepath.Join(home, ".local", "share", "educatesenv", "bin"), layout.BinDir())
+	assert.Equal(t, filepath.Join(home, ".cache", "educatesenv"), layout.CacheDir())
+	assert.Equal(t, filepath.Join(home, ".local", "state", "educatesenv", "educatesenv.lock"), layout.LockFile())
+
+	// XDG variables, ignoring relative ones as the specification requires
+	env["XDG_CONFIG_HOME"] = "/xdg/config"
+	env["XDG_DATA_HOME"] = "/xdg/data"
+	env["XDG_CACHE_HOME"] = "relative/cache"
+	env["XDG_STATE_HOME"] = "/xdg/state"
+	layout = resolve(getenv, "darwin", "")
+	assert.Equal(t, "/xdg/config/educatesenv/aliases.yaml", layout.AliasesFile())
+	assert.Equal(t, "/xdg/data/educatesenv/dev", layout.DevBuildsDir())
+	assert.Equal(t, "/xdg/data/educatesenv/plugins", layout.PluginsDir())
+	assert.Equal(t, filepath.Join(home, ".cache", "educatesenv"), layout.CacheDir())
+	assert.Equal(t, "/xdg/state/educatesenv/update-check.json", layout.UpdateCheckFile())
+	assert.Equal(t, "/xdg/state/educatesenv/trusted-projects.json", layout.TrustedProjectsFile())
+
+	// Windows keeps the legacy single directory
+	layout = resolve(getenv, "windows", "")
+	assert.True(t, layout.Single)
+	assert.Equal(t, filepath.Join(home, LegacyDirName, "bin"), layout.BinDir())
+
+	// EDUCATESENV_ROOT puts everything in one directory
+	env[RootEnv] = "/opt/educatesenv"
+	layout = resolve(getenv, "linux", "")
+	assert.Equal(t, Layout{
+		Config: "/opt/educatesenv",
+		Data:   "/opt/educatesenv",
+		Cache:  "/opt/educatesenv/cache",
+		State:  "/opt/educatesenv",
+		Single: true,
+	}, layout)
+	assert.Equal(t, "/opt/educatesenv/config.yaml", layout.ConfigFile())
+}
+
+func TestResolvePortable(t *testing.T) {
+	root := t.TempDir()
+	exe := filepath.Join(root, "educatesenv")
+	getenv := func(string) string { return "" }
+
+	// Without the marker, the executable's location does not matter
+	assert.False(t, resolve(getenv, "linux", exe).Portable)
+
+	assert.NoError(t, os.WriteFile(filepath.Join(root, PortableMarker), nil, 0o644))
+	layout := resolve(getenv, "linux", exe)
+	assert.True(t, layout.Portable)
+	assert.True(t, layout.Single)
+	assert.Equal(t, filepath.Join(root, "config.yaml"), layout.ConfigFile())
+	assert.Equal(t, filepath.Join(root, "bin"), layout.BinDir())
+	assert.Equal(t, filepath.Join(root, "cache"), layout.CacheDir())
+	assert.Equal(t, filepath.Join(root, "educatesenv.lock"), layout.LockFile())
+
+	// EDUCATESENV_ROOT still takes precedence
+	layout = resolve(func(key string) string {
+		if key == RootEnv {
+			return "/opt/educatesenv"
+		}
+		return ""
+	}, "linux", exe)
+	assert.False(t, layout.Portable)
+	assert.Equal(t, "/opt/educatesenv", layout.Config)
+}
+
+func TestLayoutAbsRel(t *testing.T) {
+	portable := Layout{Config: "/media/usb/educatesenv", Portable: true}
+	assert.Equal(t, "bin", portable.Rel("/media/usb/educatesenv/bin"))
+	assert.Equal(t, filepath.Join("dev", "main", "educates"), portable.Rel("/media/usb/educatesenv/dev/main/educates"))
+	assert.Equal(t, "/home/user/educates", portable.Rel("/home/user/educates"))
+	assert.Equal(t, "/media/usb/educatesenv-other/bin", portable.Rel("/media/usb/educatesenv-other/bin"))
+	assert.Equal(t, "/media/usb/educatesenv/bin", portable.Abs("bin"))
+	assert.Equal(t, "/home/user/educates", portable.Abs("/home/user/educates"))
+	assert.Equal(t, "", portable.Abs(""))
+
+	// Outside portable mode, paths are left alone
+	fixed := Layout{Config: "/home/user/.config/educatesenv"}
+	assert.Equal(t, "/home/user/.config/educatesenv/bin", fixed.Rel("/home/user/.config/educatesenv/bin"))
+	assert.Equal(t, "bin", fixed.Abs("bin"))
+}
+
+func TestSystemConfigFile(t *testing.T) {
+	env := map[string]string{}
+	getenv := func(key string) string { return env[key] }
+	assert.Equal(t, "/etc/educatesenv/config.yaml", systemConfigFile(getenv, "linux"))
+	assert.Equal(t, filepath.Join(`C:\ProgramData`, "educatesenv", "config.yaml"), systemConfigFile(getenv, "windows"))
+	env["ProgramData"] = `D:\ProgramData`
+	assert.Equal(t, filepath.Join(`D:\ProgramData`, "educatesenv", "config.yaml"), systemConfigFile(getenv, "windows"))
+}
+
+func TestFindProjectConfig(t *testing.T) {
+	root := t.TempDir()
+	nested := filepath.Join(root, "workshop", "content")
+	assert.NoError(t, os.MkdirAll(nested, 0o755))
+
+	_, found := FindProjectConfig(nested)
+	assert.False(t, found)
+
+	path := filepath.Join(root, ProjectConfigName)
+	assert.NoError(t, os.WriteFile(path, []byte("github:\n  org: educates\n"), 0o644))
+	foundPath, found := FindProjectConfig(nested)
+	assert.True(t, found)
+	assert.Equal(t, path, foundPath)
+}
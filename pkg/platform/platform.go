@@ -1,5 +1,7 @@
 package platform
 
+import "strings"
+
 // Operating System constants
 const (
 	// Darwin represents macOS
@@ -21,9 +23,60 @@ const (
 // BinaryPrefix is the prefix for all binary names
 const BinaryPrefix = "educates-"
 
+// ActiveBinaryBaseName is the name, without extension, of the link to the active version
+const ActiveBinaryBaseName = "educates"
+
+// ExecutableExtension returns the file extension executables use on the given OS
+func ExecutableExtension(os string) string {
+	if os == Windows {
+		return ".exe"
+	}
+	return ""
+}
+
 // GetPlatformBinaryName returns the platform-specific binary name
 func GetPlatformBinaryName(os, arch string) string {
-	return BinaryPrefix + os + "-" + arch
+	return BinaryPrefix + os + "-" + arch + ExecutableExtension(os)
+}
+
+// GetVersionBinaryName returns the file name an installed version is stored under
+func GetVersionBinaryName(version, os string) string {
+	return BinaryPrefix + version + ExecutableExtension(os)
+}
+
+// GetActiveBinaryName returns the file name of the link to the active version
+func GetActiveBinaryName(os string) string {
+	return ActiveBinaryBaseName + ExecutableExtension(os)
+}
+
+// ParseVersionBinaryName returns the version encoded in an installed binary's file name.
+// The second return value is false if the name is not a managed binary for the given OS.
+func ParseVersionBinaryName(name, os string) (string, bool) {
+	if !strings.HasPrefix(name, BinaryPrefix) {
+		return "", false
+	}
+	version := strings.TrimPrefix(name, BinaryPrefix)
+	if ext := ExecutableExtension(os); ext != "" {
+		if !strings.HasSuffix(strings.ToLower(version), ext) {
+			return "", false
+		}
+		version = version[:len(version)-len(ext)]
+	}
+	if version == "" {
+		return "", false
+	}
+	return version, true
+}
+
+// SupportedPlatforms returns the supported OS and architecture combinations
+func SupportedPlatforms() [][2]string {
+	return [][2]string{
+		{Darwin, AMD64},
+		{Darwin, ARM64},
+		{Linux, AMD64},
+		{Linux, ARM64},
+		{Windows, AMD64},
+	}
 }
 
 // IsSupportedPlatform checks if the given OS and architecture combination is supported
@@ -33,6 +86,8 @@ func IsSupportedPlatform(os, arch string) bool {
 		return arch == AMD64 || arch == ARM64
 	case Linux:
 		return arch == AMD64 || arch == ARM64
+	case Windows:
+		return arch == AMD64
 	default:
 		return false
 	}
@@ -17,8 +17,8 @@ func TestIsSupportedPlatform(t *testing.T) {
 		{"darwin-arm64", "darwin", "arm64", true},
 		{"linux-amd64", "linux", "amd64", true},
 		{"linux-arm64", "linux", "arm64", true},
-		// TODO: Implement Windows support
-		// {"windows-amd64", "windows", "amd64", true},
+		{"windows-amd64", "windows", "amd64", true},
+		{"windows-arm64", "windows", "arm64", false},
 		{"unsupported-os", "freebsd", "amd64", false},
 		{"unsupported-arch", "linux", "386", false},
 	}
@@ -31,6 +31,14 @@ func TestIsSupportedPlatform(t *testing.T) {
 	}
 }
 
+func TestSupportedPlatforms(t *testing.T) {
+	platforms := SupportedPlatforms()
+	assert.Len(t, platforms, 5)
+	for _, p := range platforms {
+		assert.True(t, IsSupportedPlatform(p[0], p[1]), "%s/%s", p[0], p[1])
+	}
+}
+
 func TestGetPlatformBinaryName(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -42,8 +50,7 @@ func TestGetPlatformBinaryName(t *testing.T) {
 		{"darwin-arm64", "darwin", "arm64", "educates-darwin-arm64"},
 		{"linux-amd64", "linux", "amd64", "educates-linux-amd64"},
 		{"linux-arm64", "linux", "arm64", "educates-linux-arm64"},
-		// TODO: Implement Windows support with .exe extension
-		// {"windows-amd64", "windows", "amd64", "educates-windows-amd64.exe"},
+		{"windows-amd64", "windows", "amd64", "educates-windows-amd64.exe"},
 	}
 
 	for _, tt := range tests {
@@ -53,3 +60,37 @@ func TestGetPlatformBinaryName(t *testing.T) {
 		})
 	}
 }
+
+func TestGetVersionBinaryName(t *testing.T) {
+	assert.Equal(t, "educates-3.2.1", GetVersionBinaryName("3.2.1", Linux))
+	assert.Equal(t, "educates-3.2.1.exe", GetVersionBinaryName("3.2.1", Windows))
+	assert.Equal(t, "educates", GetActiveBinaryName(Darwin))
+	assert.Equal(t, "educates.exe", GetActiveBinaryName(Windows))
+}
+
+func TestParseVersionBinaryName(t *testing.T) {
+	tests := []struct {
+		name     string
+		file     string
+		os       string
+		expected string
+		ok       bool
+	}{
+		{"linux", "educates-3.2.1", "linux", "3.2.1", true},
+		{"linux-active-link", "educates", "linux", "", false},
+		{"linux-other-file", "config.yaml", "linux", "", false},
+		{"windows", "educates-3.2.1.exe", "windows", "3.2.1", true},
+		{"windows-upper-ext", "educates-3.2.1.EXE", "windows", "3.2.1", true},
+		{"windows-missing-ext", "educates-3.2.1", "windows", "", false},
+		{"windows-active-link", "educates.exe", "windows", "", false},
+		{"windows-empty-version", "educates-.exe", "windows", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			version, ok := ParseVersionBinaryName(tt.file, tt.os)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.expected, version)
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package plugin
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+// Prefix is the file name prefix that marks an executable as an educatesenv plugin
+const Prefix = "educatesenv-"
+
+// Plugin is an executable discovered in a plugin search directory
+type Plugin struct {
+	// Name is the subcommand the plugin provides
+	Name string
+	// Path is the absolute path of the executable
+	Path string
+	// Shadowed is true if a plugin of the same name appears earlier in the search path
+	Shadowed bool
+}
+
+// SearchPath returns the directories searched for plugins, in order of precedence: the
+// educatesenv plugins directory, then the PATH
+func SearchPath(pluginsDir string) []string {
+	dirs := []string{pluginsDir}
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		if dir != "" {
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs
+}
+
+// Discover returns all plugins found in dirs, in search order. Plugins with the same name
+// as one found earlier are included but marked as shadowed.
+func Discover(dirs []string) []Plugin {
+	var plugins []Plugin
+	seen := map[string]bool{}
+	seenDirs := map[string]bool{}
+	for _, dir := range dirs {
+		abs, err := filepath.Abs(dir)
+		if err != nil || seenDirs[abs] {
+			continue
+		}
+		seenDirs[abs] = true
+
+		entries, err := os.ReadDir(abs)
+		if err != nil {
+			continue
+		}
+		var found []Plugin
+		for _, entry := range entries {
+			name, ok := pluginName(entry.Name())
+			if !ok {
+				continue
+			}
+			path := filepath.Join(abs, entry.Name())
+			if !isExecutable(path) {
+				continue
+			}
+			found = append(found, Plugin{Name: name, Path: path, Shadowed: seen[name]})
+		}
+		sort.Slice(found, func(i, j int) bool { return found[i].Name < found[j].Name })
+		for _, p := range found {
+			seen[p.Name] = true
+		}
+		plugins = append(plugins, found...)
+	}
+	return plugins
+}
+
+// Find returns the plugin providing the named subcommand, searching dirs in order
+func Find(name string, dirs []string) (Plugin, bool) {
+	for _, p := range Discover(dirs) {
+		if p.Name == name {
+			return p, true
+		}
+	}
+	return Plugin{}, false
+}
+
+// pluginName returns the subcommand name for a plugin file name
+func pluginName(fileName string) (string, bool) {
+	if !strings.HasPrefix(fileName, Prefix) {
+		return "", false
+	}
+	name := strings.TrimPrefix(fileName, Prefix)
+	if runtime.GOOS == "windows" {
+		ext := filepath.Ext(name)
+		if !isWindowsExecutableExt(ext) {
+			return "", false
+		}
+		name = strings.TrimSuffix(name, ext)
+	}
+	if name == "" || strings.HasPrefix(name, ".") {
+		return "", false
+	}
+	return name, true
+}
+
+// isExecutable reports whether path is a regular file the user may execute
+func isExecutable(path string) bool {
+	fi, err := os.Stat(path)
+	if err != nil || fi.IsDir() {
+		return false
+	}
+	if runtime.GOOS == "windows" {
+		return true
+	}
+	return fi.Mode().Perm()&0o111 != 0
+}
+
+// isWindowsExecutableExt reports whether ext is listed in PATHEXT
+func isWindowsExecutableExt(ext string) bool {
+	pathext := os.Getenv("PATHEXT")
+	if pathext == "" {
+		pathext = ".COM;.EXE;.BAT;.CMD"
+	}
+	for _, e := range strings.Split(pathext, ";") {
+		if e != "" && strings.EqualFold(e, ext) {
+			return true
+		}
+	}
+	return false
+}
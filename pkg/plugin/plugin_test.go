@@ -0,0 +1,49 @@
+package plugin
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiscover(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("plugins are identified by PATHEXT rather than permissions on Windows")
+	}
+
+	pluginsDir := t.TempDir()
+	pathDir := t.TempDir()
+	write := func(dir, name string, mode os.FileMode) {
+		err := os.WriteFile(filepath.Join(dir, name), []byte("#!/bin/sh\n"), mode)
+		assert.NoError(t, err)
+	}
+	write(pluginsDir, "educatesenv-sync-labs", 0o755)
+	write(pluginsDir, "educatesenv-notes", 0o644) // not executable
+	write(pathDir, "educatesenv-sync-labs", 0o755)
+	write(pathDir, "educatesenv-doctor", 0o755)
+	write(pathDir, "educatesenv-", 0o755)
+	write(pathDir, "kubectl", 0o755)
+
+	// Duplicate directories are only searched once
+	plugins := Discover([]string{pluginsDir, pathDir, pathDir})
+	assert.Equal(t, []Plugin{
+		{Name: "sync-labs", Path: filepath.Join(pluginsDir, "educatesenv-sync-labs")},
+		{Name: "doctor", Path: filepath.Join(pathDir, "educatesenv-doctor")},
+		{Name: "sync-labs", Path: filepath.Join(pathDir, "educatesenv-sync-labs"), Shadowed: true},
+	}, plugins)
+
+	p, ok := Find("sync-labs", []string{pluginsDir, pathDir})
+	assert.True(t, ok)
+	assert.Equal(t, filepath.Join(pluginsDir, "educatesenv-sync-labs"), p.Path)
+
+	_, ok = Find("notes", []string{pluginsDir, pathDir})
+	assert.False(t, ok)
+}
+
+func TestSearchPath(t *testing.T) {
+	t.Setenv("PATH", "/usr/local/bin"+string(os.PathListSeparator)+string(os.PathListSeparator)+"/usr/bin")
+	assert.Equal(t, []string{"/plugins", "/usr/local/bin", "/usr/bin"}, SearchPath("/plugins"))
+}
@@ -0,0 +1,208 @@
+package semver
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Constraint is a set of version ranges. A version satisfies the constraint if it
+// satisfies every comparison in any one of its ranges.
+type Constraint struct {
+	ranges [][]comparison
+	// prerelease is true if any comparison names a pre-release, which opts pre-releases in
+	prerelease bool
+	original   string
+}
+
+// comparison compares a version against a bound with an operator
+type comparison struct {
+	op    string
+	bound *Version
+}
+
+// ParseConstraint parses a constraint such as ">=3.2.0, <3.4.0", "~3.2", "^3.2.1", "3.2.x"
+// or "3.2 || 3.3". Comparisons separated by commas or spaces must all hold, and "||"
+// separates alternatives. A partial version without an operator, such as 3.2, matches any
+// version with that prefix. Pre-releases only satisfy a constraint that names one.
+func ParseConstraint(s string) (*Constraint, error) {
+	c := &Constraint{original: s}
+	for _, alternative := range strings.Split(s, "||") {
+		var r []comparison
+		for _, term := range strings.FieldsFunc(alternative, func(r rune) bool { return r == ',' || r == ' ' }) {
+			comparisons, named, err := parseTerm(term)
+			if err != nil {
+				return nil, fmt.Errorf("invalid constraint %q: %w", s, err)
+			}
+			if named != nil && named.IsPrerelease() {
+				c.prerelease = true
+			}
+			r = append(r, comparisons...)
+		}
+		if len(r) == 0 {
+			return nil, fmt.Errorf("invalid constraint %q: empty range", s)
+		}
+		c.ranges = append(c.ranges, r)
+	}
+	return c, nil
+}
+
+// Check reports whether v satisfies the constraint
+func (c *Constraint) Check(v *Version) bool {
+	if v.IsPrerelease() && !c.prerelease {
+		return false
+	}
+	for _, r := range c.ranges {
+		ok := true
+		for _, cmp := range r {
+			if !cmp.check(v) {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			return true
+		}
+	}
+	return false
+}
+
+// IsExact reports whether the constraint matches a single version only
+func (c *Constraint) IsExact() bool {
+	return len(c.ranges) == 1 && len(c.ranges[0]) == 1 && c.ranges[0][0].op == "="
+}
+
+// String returns the constraint as originally given
+func (c *Constraint) String() string {
+	return c.original
+}
+
+// Latest returns the highest of versions that satisfies the constraint. Versions that do
+// not parse are ignored.
+func (c *Constraint) Latest(versions []string) (string, bool) {
+	var best *Version
+	for _, s := range versions {
+		v, err := Parse(s)
+		if err != nil || !c.Check(v) {
+			continue
+		}
+		if best == nil || v.Compare(best) > 0 {
+			best = v
+		}
+	}
+	if best == nil {
+		return "", false
+	}
+	return best.Original, true
+}
+
+func (cmp comparison) check(v *Version) bool {
+	c := v.Compare(cmp.bound)
+	switch cmp.op {
+	case "=":
+		return c == 0
+	case "!=":
+		return c != 0
+	case ">":
+		return c > 0
+	case ">=":
+		return c >= 0
+	case "<":
+		return c < 0
+	default: // "<="
+		return c <= 0
+	}
+}
+
+// parseTerm turns one term of a range into the comparisons it stands for, and returns the
+// version it names, if any
+func parseTerm(term string) ([]comparison, *Version, error) {
+	op := ""
+	for _, candidate := range []string{">=", "<=", "!=", ">", "<", "=", "~", "^"} {
+		if strings.HasPrefix(term, candidate) {
+			op = candidate
+			break
+		}
+	}
+	rest := strings.TrimPrefix(strings.TrimPrefix(term, op), "v")
+
+	// Count the version parts given, treating x and * as missing
+	parts := strings.Split(strings.SplitN(rest, "-", 2)[0], ".")
+	given := 0
+	for _, part := range parts {
+		if part == "x" || part == "X" || part == "*" {
+			break
+		}
+		given++
+	}
+	if given < len(parts) {
+		// A wildcard ends the version, so 3.x means 3
+		rest = strings.Join(parts[:given], ".")
+	}
+	if given == 0 {
+		if op != "" && op != "=" {
+			return nil, nil, fmt.Errorf("%q has no version", term)
+		}
+		return []comparison{{op: ">=", bound: &Version{Original: "0.0.0"}}}, nil, nil
+	}
+
+	bound, err := Parse(rest)
+	if err != nil {
+		return nil, nil, err
+	}
+	if given < 3 && bound.IsPrerelease() {
+		return nil, nil, fmt.Errorf("%q has a pre-release but no patch version", term)
+	}
+
+	switch op {
+	case "", "=":
+		if given == 3 {
+			return []comparison{{op: "=", bound: bound}}, bound, nil
+		}
+		return []comparison{{op: ">=", bound: bound}, {op: "<", bound: bump(bound, given-1)}}, bound, nil
+	case "~":
+		// ~3.2.1 allows patch updates, ~3 allows minor updates
+		level := 1
+		if given == 1 {
+			level = 0
+		}
+		return []comparison{{op: ">=", bound: bound}, {op: "<", bound: bump(bound, level)}}, bound, nil
+	case "^":
+		// ^3.2.1 allows updates that do not change the leftmost non-zero part
+		level := 0
+		switch {
+		case bound.Major == 0 && (bound.Minor != 0 || given == 2):
+			level = 1
+		case bound.Major == 0 && given == 3:
+			level = 2
+		}
+		return []comparison{{op: ">=", bound: bound}, {op: "<", bound: bump(bound, level)}}, bound, nil
+	default:
+		if given < 3 {
+			// >3.2 means above every 3.2.x, <=3.2 includes every 3.2.x
+			switch op {
+			case ">":
+				return []comparison{{op: ">=", bound: bump(bound, given-1)}}, bound, nil
+			case "<=":
+				return []comparison{{op: "<", bound: bump(bound, given-1)}}, bound, nil
+			}
+		}
+		return []comparison{{op: op, bound: bound}}, bound, nil
+	}
+}
+
+// bump returns the lowest pre-release of the version after v at the given level, where 0
+// is the major, 1 the minor and 2 the patch version. Using the lowest pre-release as the
+// upper bound excludes pre-releases of that version from ranges below it.
+func bump(v *Version, level int) *Version {
+	next := &Version{Major: v.Major, Minor: v.Minor, Patch: v.Patch, Prerelease: "0"}
+	switch level {
+	case 0:
+		next.Major, next.Minor, next.Patch = v.Major+1, 0, 0
+	case 1:
+		next.Minor, next.Patch = v.Minor+1, 0
+	default:
+		next.Patch = v.Patch + 1
+	}
+	next.Original = fmt.Sprintf("%d.%d.%d-0", next.Major, next.Minor, next.Patch)
+	return next
+}
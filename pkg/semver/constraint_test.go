@@ -0,0 +1,77 @@
+package semver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConstraintCheck(t *testing.T) {
+	tests := []struct {
+		constraint string
+		matches    []string
+		rejects    []string
+	}{
+		{"3.2.1", []string{"3.2.1", "v3.2.1"}, []string{"3.2.0", "3.2.2", "3.2.1-rc.1"}},
+		{"3.2", []string{"3.2.0", "3.2.9"}, []string{"3.1.9", "3.3.0", "3.2.1-rc.1"}},
+		{"3.2.x", []string{"3.2.0", "3.2.9"}, []string{"3.3.0"}},
+		{"3", []string{"3.0.0", "3.9.1"}, []string{"2.9.0", "4.0.0"}},
+		{"*", []string{"0.0.1", "9.9.9"}, []string{"1.0.0-rc.1"}},
+		{"~3.2.1", []string{"3.2.1", "3.2.7"}, []string{"3.2.0", "3.3.0"}},
+		{"~3.2", []string{"3.2.0", "3.2.7"}, []string{"3.3.0"}},
+		{"~3", []string{"3.0.0", "3.5.0"}, []string{"4.0.0"}},
+		{"^3.2.1", []string{"3.2.1", "3.9.0"}, []string{"3.2.0", "4.0.0"}},
+		{"^0.2.1", []string{"0.2.1", "0.2.9"}, []string{"0.3.0"}},
+		{"^0.0.3", []string{"0.0.3"}, []string{"0.0.4"}},
+		{">=3.2.0, <3.4.0", []string{"3.2.0", "3.3.9"}, []string{"3.1.0", "3.4.0", "3.4.0-rc.1"}},
+		{">=3.2.0 <3.4.0", []string{"3.3.0"}, []string{"3.4.0"}},
+		{">3.2", []string{"3.3.0"}, []string{"3.2.5"}},
+		{"<=3.2", []string{"3.2.5", "3.1.0"}, []string{"3.3.0"}},
+		{"!=3.2.1", []string{"3.2.0"}, []string{"3.2.1"}},
+		{"3.1 || >=3.3.0", []string{"3.1.4", "3.3.0"}, []string{"3.2.0"}},
+		{">=3.3.0-rc.1", []string{"3.3.0-rc.2", "3.3.0"}, []string{"3.3.0-beta.1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.constraint, func(t *testing.T) {
+			c, err := ParseConstraint(tt.constraint)
+			assert.NoError(t, err)
+			for _, s := range tt.matches {
+				v, err := Parse(s)
+				assert.NoError(t, err)
+				assert.True(t, c.Check(v), "%s should match %s", tt.constraint, s)
+			}
+			for _, s := range tt.rejects {
+				v, err := Parse(s)
+				assert.NoError(t, err)
+				assert.False(t, c.Check(v), "%s should not match %s", tt.constraint, s)
+			}
+		})
+	}
+}
+
+func TestParseConstraintInvalid(t *testing.T) {
+	for _, s := range []string{"", ">=", "~x", "3.2.a", ">=3.2.0 ||", "3.2-rc.1"} {
+		_, err := ParseConstraint(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestConstraintLatest(t *testing.T) {
+	c, err := ParseConstraint("~3.2")
+	assert.NoError(t, err)
+
+	latest, ok := c.Latest([]string{"3.1.9", "3.2.10", "3.2.9", "3.2.11-rc.1", "3.3.0", "develop"})
+	assert.True(t, ok)
+	assert.Equal(t, "3.2.10", latest)
+
+	_, ok = c.Latest([]string{"3.1.0"})
+	assert.False(t, ok)
+}
+
+func TestConstraintIsExact(t *testing.T) {
+	for s, exact := range map[string]bool{"3.2.1": true, "=v3.2.1": true, "3.2": false, "~3.2.1": false, "3.2.1 || 3.2.2": false} {
+		c, err := ParseConstraint(s)
+		assert.NoError(t, err)
+		assert.Equal(t, exact, c.IsExact(), s)
+	}
+}
@@ -0,0 +1,139 @@
+package semver
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Version is a parsed semantic version. Release tags may carry a leading "v".
+type Version struct {
+	Major      int
+	Minor      int
+	Patch      int
+	Prerelease string
+	Original   string
+}
+
+// Parse parses a semantic version such as 3.2.1, v3.2.1 or 3.3.0-rc.1. Missing minor
+// and patch numbers default to zero, and build metadata is ignored.
+func Parse(s string) (*Version, error) {
+	v := &Version{Original: s}
+	rest := strings.TrimPrefix(strings.TrimSpace(s), "v")
+	if i := strings.IndexByte(rest, '+'); i >= 0 {
+		rest = rest[:i]
+	}
+	if i := strings.IndexByte(rest, '-'); i >= 0 {
+		v.Prerelease = rest[i+1:]
+		rest = rest[:i]
+		if v.Prerelease == "" {
+			return nil, fmt.Errorf("invalid version %q: empty pre-release", s)
+		}
+	}
+
+	parts := strings.Split(rest, ".")
+	if len(parts) > 3 || parts[0] == "" {
+		return nil, fmt.Errorf("invalid version %q", s)
+	}
+	nums := []*int{&v.Major, &v.Minor, &v.Patch}
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid version %q", s)
+		}
+		*nums[i] = n
+	}
+	return v, nil
+}
+
+// IsPrerelease reports whether the version has a pre-release suffix
+func (v *Version) IsPrerelease() bool {
+	return v.Prerelease != ""
+}
+
+// String returns the version as originally given
+func (v *Version) String() string {
+	return v.Original
+}
+
+// Compare returns -1, 0 or 1 depending on whether v is lower than, equal to or higher
+// than other, following semantic versioning precedence rules
+func (v *Version) Compare(other *Version) int {
+	for _, d := range []int{v.Major - other.Major, v.Minor - other.Minor, v.Patch - other.Patch} {
+		if d != 0 {
+			return sign(d)
+		}
+	}
+	return comparePrerelease(v.Prerelease, other.Prerelease)
+}
+
+// Compare compares two version strings. Versions that do not parse sort before all valid
+// versions and are compared lexically among themselves.
+func Compare(a, b string) int {
+	va, errA := Parse(a)
+	vb, errB := Parse(b)
+	switch {
+	case errA != nil && errB != nil:
+		return strings.Compare(a, b)
+	case errA != nil:
+		return -1
+	case errB != nil:
+		return 1
+	default:
+		return va.Compare(vb)
+	}
+}
+
+// Sort sorts version strings in ascending semantic version order
+func Sort(versions []string) {
+	sort.SliceStable(versions, func(i, j int) bool {
+		return Compare(versions[i], versions[j]) < 0
+	})
+}
+
+// comparePrerelease compares pre-release suffixes. A release without one is higher than
+// any pre-release, numeric identifiers compare numerically and are lower than alphanumeric ones.
+func comparePrerelease(a, b string) int {
+	if a == b {
+		return 0
+	}
+	if a == "" {
+		return 1
+	}
+	if b == "" {
+		return -1
+	}
+
+	pa, pb := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(pa) && i < len(pb); i++ {
+		na, errA := strconv.Atoi(pa[i])
+		nb, errB := strconv.Atoi(pb[i])
+		switch {
+		case errA == nil && errB == nil:
+			if na != nb {
+				return sign(na - nb)
+			}
+		case errA == nil:
+			return -1
+		case errB == nil:
+			return 1
+		default:
+			if c := strings.Compare(pa[i], pb[i]); c != 0 {
+				return c
+			}
+		}
+	}
+	return sign(len(pa) - len(pb))
+}
+
+func sign(n int) int {
+	switch {
+	case n < 0:
+		return -1
+	case n > 0:
+		return 1
+	default:
+		return 0
+	}
+}
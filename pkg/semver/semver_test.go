@@ -0,0 +1,76 @@
+package semver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input   string
+		major   int
+		minor   int
+		patch   int
+		pre     string
+		wantErr bool
+	}{
+		{"3.2.1", 3, 2, 1, "", false},
+		{"v3.2.1", 3, 2, 1, "", false},
+		{"3.3.0-rc.1", 3, 3, 0, "rc.1", false},
+		{"3.2", 3, 2, 0, "", false},
+		{"3.2.1+build.5", 3, 2, 1, "", false},
+		{"develop", 0, 0, 0, "", true},
+		{"3.2.1.4", 0, 0, 0, "", true},
+		{"3.2.1-", 0, 0, 0, "", true},
+		{"", 0, 0, 0, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			v, err := Parse(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.major, v.Major)
+			assert.Equal(t, tt.minor, v.Minor)
+			assert.Equal(t, tt.patch, v.Patch)
+			assert.Equal(t, tt.pre, v.Prerelease)
+			assert.Equal(t, tt.input, v.String())
+		})
+	}
+}
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{"3.2.1", "3.2.1", 0},
+		{"3.2.1", "v3.2.1", 0},
+		{"3.2.10", "3.2.9", 1},
+		{"3.10.0", "3.9.9", 1},
+		{"3.3.0-rc.1", "3.3.0", -1},
+		{"3.3.0-rc.2", "3.3.0-rc.10", -1},
+		{"3.3.0-alpha", "3.3.0-beta", -1},
+		{"3.3.0-rc.1", "3.3.0-rc.1.1", -1},
+		{"3.3.0-1", "3.3.0-alpha", -1},
+		{"develop", "0.0.1", -1},
+		{"abc", "abd", -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+"_"+tt.b, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Compare(tt.a, tt.b))
+			assert.Equal(t, -tt.expected, Compare(tt.b, tt.a))
+		})
+	}
+}
+
+func TestSort(t *testing.T) {
+	versions := []string{"3.10.0", "3.2.1", "3.3.0-rc.1", "custom", "3.3.0", "3.2.10"}
+	Sort(versions)
+	assert.Equal(t, []string{"custom", "3.2.1", "3.2.10", "3.3.0-rc.1", "3.3.0", "3.10.0"}, versions)
+}
@@ -0,0 +1,108 @@
+package shell
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+const (
+	// blockStart marks the beginning of the educatesenv block in a shell profile
+	blockStart = "# >>> educatesenv >>>"
+	// blockEnd marks the end of the educatesenv block in a shell profile
+	blockEnd = "# <<< educatesenv <<<"
+)
+
+// ProfilePath returns the rc file the given shell reads for interactive sessions
+func ProfilePath(sh, home string) (string, error) {
+	switch sh {
+	case Bash:
+		if runtime.GOOS == "darwin" {
+			// Terminal.app starts login shells, which read .bash_profile but not .bashrc
+			return filepath.Join(home, ".bash_profile"), nil
+		}
+		return filepath.Join(home, ".bashrc"), nil
+	case Zsh:
+		if zdotdir := os.Getenv("ZDOTDIR"); zdotdir != "" {
+			return filepath.Join(zdotdir, ".zshrc"), nil
+		}
+		return filepath.Join(home, ".zshrc"), nil
+	case Fish:
+		configHome := os.Getenv("XDG_CONFIG_HOME")
+		if configHome == "" {
+			configHome = filepath.Join(home, ".config")
+		}
+		return filepath.Join(configHome, "fish", "config.fish"), nil
+	case PowerShell:
+		if runtime.GOOS == "windows" {
+			return filepath.Join(home, "Documents", "PowerShell", "Microsoft.PowerShell_profile.ps1"), nil
+		}
+		return filepath.Join(home, ".config", "powershell", "Microsoft.PowerShell_profile.ps1"), nil
+	default:
+		return "", fmt.Errorf("unsupported shell %q. Supported shells are: %s", sh, strings.Join(Supported(), ", "))
+	}
+}
+
+// ProfileSnippet returns the line a shell profile needs to load the educatesenv environment
+func ProfileSnippet(sh, executable string) (string, error) {
+	switch sh {
+	case Bash, Zsh:
+		return fmt.Sprintf("eval \"$(%s env --shell %s)\"", quotePosix(executable), sh), nil
+	case Fish:
+		return fmt.Sprintf("%s env --shell fish | source", quoteFish(executable)), nil
+	case PowerShell:
+		return fmt.Sprintf("& %s env --shell powershell | Out-String | Invoke-Expression", quotePowerShell(executable)), nil
+	default:
+		return "", fmt.Errorf("unsupported shell %q. Supported shells are: %s", sh, strings.Join(Supported(), ", "))
+	}
+}
+
+// UpdateProfile inserts snippet into the profile at path inside a marked block. An existing
+// block is replaced in place, so running it repeatedly never duplicates the setup. It
+// reports whether the file was changed.
+func UpdateProfile(path, snippet string) (bool, error) {
+	block := blockStart + "\n" + strings.TrimRight(snippet, "\n") + "\n" + blockEnd + "\n"
+
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return false, fmt.Errorf("failed to read profile %s: %w", path, err)
+	}
+	content := string(data)
+
+	var updated string
+	start := strings.Index(content, blockStart)
+	if start >= 0 {
+		end := strings.Index(content[start:], blockEnd)
+		if end < 0 {
+			return false, fmt.Errorf("profile %s has an unterminated educatesenv block; add %q after it or remove it", path, blockEnd)
+		}
+		end += start + len(blockEnd)
+		if end < len(content) && content[end] == '\n' {
+			end++
+		}
+		updated = content[:start] + block + content[end:]
+	} else {
+		updated = content
+		if updated != "" && !strings.HasSuffix(updated, "\n") {
+			updated += "\n"
+		}
+		if updated != "" {
+			updated += "\n"
+		}
+		updated += block
+	}
+
+	if updated == content {
+		return false, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return false, fmt.Errorf("failed to create directory for profile %s: %w", path, err)
+	}
+	if err := os.WriteFile(path, []byte(updated), 0o644); err != nil {
+		return false, fmt.Errorf("failed to write profile %s: %w", path, err)
+	}
+	return true, nil
+}
@@ -0,0 +1,129 @@
+package shell
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+)
+
+// Supported shells
+const (
+	// Bash is the GNU Bourne-Again shell
+	Bash = "bash"
+	// Zsh is the Z shell
+	Zsh = "zsh"
+	// Fish is the friendly interactive shell
+	Fish = "fish"
+	// PowerShell is Windows PowerShell or PowerShell Core
+	PowerShell = "powershell"
+)
+
+// Supported returns the names of the supported shells
+func Supported() []string {
+	return []string{Bash, Zsh, Fish, PowerShell}
+}
+
+// EnvOptions controls what Env emits besides the PATH setup
+type EnvOptions struct {
+	// Executable is the path used to invoke educatesenv from the generated code
+	Executable string
+	// BinDir is the directory holding the active educates binary
+	BinDir string
+	// Completion registers shell completion for educatesenv
+	Completion bool
+	// Hook wraps educatesenv in a shell function that clears the shell's command
+	// lookup cache after each run, so a switched version is picked up immediately
+	Hook bool
+}
+
+// Parse normalizes a shell name, accepting common aliases such as pwsh
+func Parse(name string) (string, error) {
+	// Accept both separators, as $SHELL may hold a Windows path when running under MSYS
+	base := name[strings.LastIndexAny(name, `/\`)+1:]
+	switch strings.ToLower(strings.TrimSuffix(base, ".exe")) {
+	case Bash:
+		return Bash, nil
+	case Zsh:
+		return Zsh, nil
+	case Fish:
+		return Fish, nil
+	case PowerShell, "pwsh":
+		return PowerShell, nil
+	default:
+		return "", fmt.Errorf("unsupported shell %q. Supported shells are: %s", name, strings.Join(Supported(), ", "))
+	}
+}
+
+// Detect returns the user's shell, falling back to bash, or PowerShell on Windows
+func Detect() string {
+	if sh, err := Parse(os.Getenv("SHELL")); err == nil {
+		return sh
+	}
+	if runtime.GOOS == "windows" {
+		return PowerShell
+	}
+	return Bash
+}
+
+// Env returns code that, when evaluated by the given shell, puts the bin directory on the
+// PATH and optionally registers completion and the rehash hook
+func Env(sh string, opts EnvOptions) (string, error) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# educatesenv shell integration for %s\n", sh)
+
+	switch sh {
+	case Bash, Zsh:
+		bin := quotePosix(opts.BinDir)
+		exe := quotePosix(opts.Executable)
+		fmt.Fprintf(&b, "case \":${PATH}:\" in\n  *:%s:*) ;;\n  *) export PATH=%s\":${PATH}\" ;;\nesac\n", bin, bin)
+		if opts.Completion {
+			if sh == Bash {
+				// The bash 3.2 that ships with macOS cannot source a process substitution
+				fmt.Fprintf(&b, "eval \"$(%s completion bash)\"\n", exe)
+			} else {
+				fmt.Fprintf(&b, "if (( $+functions[compdef] )); then\n  source <(%s completion zsh)\nfi\n", exe)
+			}
+		}
+		if opts.Hook {
+			rehash := "hash -r 2>/dev/null"
+			if sh == Zsh {
+				rehash = "rehash"
+			}
+			fmt.Fprintf(&b, "educatesenv() {\n  command %s \"$@\"\n  local ret=$?\n  %s\n  return $ret\n}\n", exe, rehash)
+		}
+	case Fish:
+		bin := quoteFish(opts.BinDir)
+		fmt.Fprintf(&b, "if not contains -- %s $PATH\n    set -gx PATH %s $PATH\nend\n", bin, bin)
+		if opts.Completion {
+			fmt.Fprintf(&b, "%s completion fish | source\n", quoteFish(opts.Executable))
+		}
+		// fish does not cache command locations, so no hook is needed
+	case PowerShell:
+		bin := quotePowerShell(opts.BinDir)
+		fmt.Fprintf(&b, "if (-not (($Env:Path -split [IO.Path]::PathSeparator) -contains %s)) {\n  $Env:Path = %s + [IO.Path]::PathSeparator + $Env:Path\n}\n", bin, bin)
+		if opts.Completion {
+			fmt.Fprintf(&b, "& %s completion powershell | Out-String | Invoke-Expression\n", quotePowerShell(opts.Executable))
+		}
+		// PowerShell resolves commands on every invocation, so no hook is needed
+	default:
+		return "", fmt.Errorf("unsupported shell %q. Supported shells are: %s", sh, strings.Join(Supported(), ", "))
+	}
+
+	return b.String(), nil
+}
+
+// quotePosix quotes s for bash and zsh
+func quotePosix(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// quoteFish quotes s for fish
+func quoteFish(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(s) + "'"
+}
+
+// quotePowerShell quotes s for PowerShell
+func quotePowerShell(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
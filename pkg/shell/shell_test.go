@@ -0,0 +1,114 @@
+package shell
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+		wantErr  bool
+	}{
+		{"bash", "bash", Bash, false},
+		{"bash-path", "/bin/bash", Bash, false},
+		{"zsh-path", "/usr/local/bin/zsh", Zsh, false},
+		{"fish", "fish", Fish, false},
+		{"pwsh", "pwsh", PowerShell, false},
+		{"pwsh-exe", `C:\Program Files\PowerShell\7\pwsh.exe`, PowerShell, false},
+		{"unsupported", "tcsh", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Parse(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestEnv(t *testing.T) {
+	opts := EnvOptions{
+		Executable: "/opt/educatesenv",
+		BinDir:     "/home/user/.educatesenv/bin",
+		Completion: true,
+	}
+
+	out, err := Env(Bash, opts)
+	assert.NoError(t, err)
+	assert.Contains(t, out, `export PATH='/home/user/.educatesenv/bin'":${PATH}"`)
+	assert.Contains(t, out, `eval "$('/opt/educatesenv' completion bash)"`)
+	assert.NotContains(t, out, "source <(")
+	assert.NotContains(t, out, "hash -r")
+
+	opts.Hook = true
+	out, err = Env(Zsh, opts)
+	assert.NoError(t, err)
+	assert.Contains(t, out, "completion zsh")
+	assert.Contains(t, out, "rehash")
+
+	opts.Completion = false
+	out, err = Env(Fish, opts)
+	assert.NoError(t, err)
+	assert.Contains(t, out, "set -gx PATH '/home/user/.educatesenv/bin' $PATH")
+	assert.NotContains(t, out, "completion")
+
+	opts.BinDir = `C:\Users\o'brien\.educatesenv\bin`
+	out, err = Env(PowerShell, opts)
+	assert.NoError(t, err)
+	assert.Contains(t, out, `'C:\Users\o''brien\.educatesenv\bin'`)
+
+	_, err = Env("tcsh", opts)
+	assert.Error(t, err)
+}
+
+func TestUpdateProfile(t *testing.T) {
+	tmpDir := t.TempDir()
+	profile := filepath.Join(tmpDir, "nested", ".bashrc")
+
+	// A missing profile is created
+	changed, err := UpdateProfile(profile, `eval "$(educatesenv env --shell bash)"`)
+	assert.NoError(t, err)
+	assert.True(t, changed)
+
+	// Running again with the same snippet is a no-op
+	changed, err = UpdateProfile(profile, `eval "$(educatesenv env --shell bash)"`)
+	assert.NoError(t, err)
+	assert.False(t, changed)
+
+	// Existing content around the block is preserved and the block is replaced in place
+	data, err := os.ReadFile(profile)
+	assert.NoError(t, err)
+	err = os.WriteFile(profile, []byte("alias ll='ls -l'\n"+string(data)+"export EDITOR=vim"), 0o644)
+	assert.NoError(t, err)
+
+	changed, err = UpdateProfile(profile, `eval "$(/new/educatesenv env --shell bash)"`)
+	assert.NoError(t, err)
+	assert.True(t, changed)
+
+	data, err = os.ReadFile(profile)
+	assert.NoError(t, err)
+	content := string(data)
+	assert.Equal(t, 1, strings.Count(content, blockStart))
+	assert.Contains(t, content, "/new/educatesenv")
+	assert.NotContains(t, content, `"$(educatesenv`)
+	assert.True(t, strings.HasPrefix(content, "alias ll='ls -l'\n"))
+	assert.True(t, strings.HasSuffix(content, "export EDITOR=vim"))
+
+	// An unterminated block is reported rather than duplicated
+	err = os.WriteFile(profile, []byte(blockStart+"\nsomething\n"), 0o644)
+	assert.NoError(t, err)
+	_, err = UpdateProfile(profile, "snippet")
+	assert.Error(t, err)
+}
@@ -0,0 +1,27 @@
+package verify
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+)
+
+// ChecksumFileNames are the names release checksum files are commonly published under
+var ChecksumFileNames = []string{"checksums.txt", "SHA256SUMS", "sha256sums.txt"}
+
+// LookupChecksum returns the hex encoded SHA-256 digest listed for name in a checksum file
+// in the format written by sha256sum and goreleaser
+func LookupChecksum(checksums []byte, name string) (string, bool) {
+	scanner := bufio.NewScanner(bytes.NewReader(checksums))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 {
+			continue
+		}
+		// sha256sum marks binary mode with a leading '*'
+		if strings.TrimPrefix(fields[1], "*") == name {
+			return strings.ToLower(fields[0]), true
+		}
+	}
+	return "", false
+}
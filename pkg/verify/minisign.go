@@ -0,0 +1,84 @@
+package verify
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/blake2b"
+)
+
+const (
+	// minisignKeyEncodedLen is the length of a base64 encoded minisign public key
+	minisignKeyEncodedLen = 56
+	// minisignAlgorithm signs the message itself
+	minisignAlgorithm = "Ed"
+	// minisignHashedAlgorithm signs the BLAKE2b-512 hash of the message
+	minisignHashedAlgorithm = "ED"
+	// trustedCommentPrefix starts the trusted comment line of a minisign signature
+	trustedCommentPrefix = "trusted comment: "
+)
+
+// minisignKey is an Ed25519 public key with its minisign key id
+type minisignKey struct {
+	id  []byte
+	key ed25519.PublicKey
+}
+
+// parseMinisignKey parses a minisign public key, with or without its untrusted comment line
+func parseMinisignKey(text string) (minisignKey, error) {
+	lines := strings.Split(strings.TrimSpace(text), "\n")
+	encoded := strings.TrimSpace(lines[len(lines)-1])
+
+	raw, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || len(raw) != 2+8+ed25519.PublicKeySize || string(raw[:2]) != minisignAlgorithm {
+		return minisignKey{}, fmt.Errorf("invalid public key: expected a PEM encoded public key or a minisign public key")
+	}
+	return minisignKey{id: raw[2:10], key: ed25519.PublicKey(raw[10:])}, nil
+}
+
+// verifyMinisign verifies a minisign signature file, including its trusted comment
+func (v *Verifier) verifyMinisign(message, signature []byte) error {
+	lines := strings.Split(strings.ReplaceAll(string(signature), "\r\n", "\n"), "\n")
+	if len(lines) < 4 || !strings.HasPrefix(lines[2], trustedCommentPrefix) {
+		return fmt.Errorf("invalid minisign signature: unexpected format")
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(lines[1]))
+	if err != nil || len(raw) != 2+8+ed25519.SignatureSize {
+		return fmt.Errorf("invalid minisign signature: malformed signature line")
+	}
+	algorithm, keyID, sig := string(raw[:2]), raw[2:10], raw[10:]
+
+	globalSig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(lines[3]))
+	if err != nil || len(globalSig) != ed25519.SignatureSize {
+		return fmt.Errorf("invalid minisign signature: malformed trusted comment signature")
+	}
+	trustedComment := strings.TrimPrefix(lines[2], trustedCommentPrefix)
+
+	signed := message
+	switch algorithm {
+	case minisignAlgorithm:
+	case minisignHashedAlgorithm:
+		digest := blake2b.Sum512(message)
+		signed = digest[:]
+	default:
+		return fmt.Errorf("invalid minisign signature: unsupported algorithm %q", algorithm)
+	}
+
+	for _, mk := range v.minisignKeys {
+		if !bytes.Equal(mk.id, keyID) {
+			continue
+		}
+		if !ed25519.Verify(mk.key, signed, sig) {
+			return fmt.Errorf("minisign signature verification failed")
+		}
+		if !ed25519.Verify(mk.key, append(append([]byte{}, sig...), trustedComment...), globalSig) {
+			return fmt.Errorf("minisign trusted comment verification failed")
+		}
+		return nil
+	}
+	return ErrNoMatchingKey
+}
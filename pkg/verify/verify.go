@@ -0,0 +1,175 @@
+package verify
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	_ "crypto/sha512" // registers SHA-384 and SHA-512 for curveHash
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Signature file suffixes, in order of preference
+const (
+	// CosignSuffix is a base64 encoded signature created with `cosign sign-blob --key`
+	CosignSuffix = ".sig"
+	// BundleSuffix is a cosign or sigstore bundle
+	BundleSuffix = ".bundle"
+	// MinisignSuffix is a minisign signature
+	MinisignSuffix = ".minisig"
+)
+
+// Suffixes returns the signature file suffixes that can be verified
+func Suffixes() []string {
+	return []string{CosignSuffix, BundleSuffix, MinisignSuffix}
+}
+
+// ErrNoMatchingKey is returned when none of the trusted keys verifies a signature
+var ErrNoMatchingKey = errors.New("signature does not match any of the configured public keys")
+
+// Verifier checks detached signatures offline against a set of trusted public keys
+type Verifier struct {
+	keys         []crypto.PublicKey
+	minisignKeys []minisignKey
+}
+
+// NewVerifier parses trusted public keys. Each key is either the key itself, as a PEM encoded
+// PKIX public key (cosign) or a minisign public key, or the path to a file containing one.
+func NewVerifier(keys []string) (*Verifier, error) {
+	v := &Verifier{}
+	for _, key := range keys {
+		text := strings.TrimSpace(key)
+		if !looksLikeKey(text) {
+			data, err := os.ReadFile(text)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read public key file %s: %w", text, err)
+			}
+			text = strings.TrimSpace(string(data))
+		}
+
+		if block, _ := pem.Decode([]byte(text)); block != nil {
+			pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse PEM public key: %w", err)
+			}
+			v.keys = append(v.keys, pub)
+			continue
+		}
+
+		mk, err := parseMinisignKey(text)
+		if err != nil {
+			return nil, err
+		}
+		v.minisignKeys = append(v.minisignKeys, mk)
+	}
+	return v, nil
+}
+
+// Verify checks that signature, in the format identified by its file suffix, signs message
+func (v *Verifier) Verify(suffix string, message, signature []byte) error {
+	switch suffix {
+	case CosignSuffix:
+		sig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(signature)))
+		if err != nil {
+			return fmt.Errorf("failed to decode cosign signature: %w", err)
+		}
+		return v.verifyPKIX(message, sig)
+	case BundleSuffix:
+		sig, err := bundleSignature(signature)
+		if err != nil {
+			return err
+		}
+		return v.verifyPKIX(message, sig)
+	case MinisignSuffix:
+		return v.verifyMinisign(message, signature)
+	default:
+		return fmt.Errorf("unsupported signature format %q", suffix)
+	}
+}
+
+// verifyPKIX verifies a raw signature over message against the PEM encoded keys
+func (v *Verifier) verifyPKIX(message, sig []byte) error {
+	digest := sha256.Sum256(message)
+	for _, key := range v.keys {
+		switch pub := key.(type) {
+		case *ecdsa.PublicKey:
+			hash := curveHash(pub.Curve)
+			h := hash.New()
+			h.Write(message)
+			if ecdsa.VerifyASN1(pub, h.Sum(nil), sig) {
+				return nil
+			}
+		case ed25519.PublicKey:
+			if ed25519.Verify(pub, message, sig) {
+				return nil
+			}
+		case *rsa.PublicKey:
+			if rsa.VerifyPKCS1v15(pub, crypto.SHA256, digest[:], sig) == nil {
+				return nil
+			}
+			if rsa.VerifyPSS(pub, crypto.SHA256, digest[:], sig, nil) == nil {
+				return nil
+			}
+		}
+	}
+	return ErrNoMatchingKey
+}
+
+// curveHash returns the hash signatures are made with for keys on curve, which matches the
+// strength of the curve as cosign does: SHA-384 for P-384, SHA-512 for P-521 and SHA-256
+// otherwise
+func curveHash(curve elliptic.Curve) crypto.Hash {
+	switch curve {
+	case elliptic.P384():
+		return crypto.SHA384
+	case elliptic.P521():
+		return crypto.SHA512
+	default:
+		return crypto.SHA256
+	}
+}
+
+// bundleSignature extracts the signature from a cosign bundle or a sigstore bundle. The
+// transparency log entry is not checked, as verification happens offline against a key.
+func bundleSignature(data []byte) ([]byte, error) {
+	var bundle struct {
+		Base64Signature  string `json:"base64Signature"`
+		MessageSignature struct {
+			Signature string `json:"signature"`
+		} `json:"messageSignature"`
+	}
+	if err := json.Unmarshal(data, &bundle); err != nil {
+		return nil, fmt.Errorf("failed to parse signature bundle: %w", err)
+	}
+
+	encoded := bundle.Base64Signature
+	if encoded == "" {
+		encoded = bundle.MessageSignature.Signature
+	}
+	if encoded == "" {
+		return nil, fmt.Errorf("signature bundle contains no signature")
+	}
+	sig, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode bundle signature: %w", err)
+	}
+	return sig, nil
+}
+
+// looksLikeKey reports whether text is an inline key rather than a file path
+func looksLikeKey(text string) bool {
+	if strings.HasPrefix(text, "-----BEGIN") || strings.HasPrefix(text, "untrusted comment:") {
+		return true
+	}
+	_, err := base64.StdEncoding.DecodeString(text)
+	return err == nil && len(text) == minisignKeyEncodedLen
+}
@@ -0,0 +1,177 @@
+package verify
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/blake2b"
+)
+
+func pemPublicKey(t *testing.T, pub any) string {
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	assert.NoError(t, err)
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+}
+
+func TestVerifyCosign(t *testing.T) {
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	other, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	message := []byte("educates binary")
+	digest := sha256.Sum256(message)
+	sig, err := ecdsa.SignASN1(rand.Reader, priv, digest[:])
+	assert.NoError(t, err)
+	encoded := []byte(base64.StdEncoding.EncodeToString(sig) + "\n")
+
+	// Keys can be given inline or as a file path
+	keyFile := filepath.Join(t.TempDir(), "cosign.pub")
+	err = os.WriteFile(keyFile, []byte(pemPublicKey(t, &priv.PublicKey)), 0o644)
+	assert.NoError(t, err)
+
+	v, err := NewVerifier([]string{pemPublicKey(t, &other.PublicKey), keyFile})
+	assert.NoError(t, err)
+	assert.NoError(t, v.Verify(CosignSuffix, message, encoded))
+	assert.Error(t, v.Verify(CosignSuffix, []byte("tampered"), encoded))
+
+	// A bundle carries the same signature
+	bundle := []byte(fmt.Sprintf(`{"base64Signature": %q, "cert": ""}`, base64.StdEncoding.EncodeToString(sig)))
+	assert.NoError(t, v.Verify(BundleSuffix, message, bundle))
+	bundle = []byte(fmt.Sprintf(`{"messageSignature": {"signature": %q}}`, base64.StdEncoding.EncodeToString(sig)))
+	assert.NoError(t, v.Verify(BundleSuffix, message, bundle))
+
+	// Without the signing key, verification fails
+	v, err = NewVerifier([]string{pemPublicKey(t, &other.PublicKey)})
+	assert.NoError(t, err)
+	assert.ErrorIs(t, v.Verify(CosignSuffix, message, encoded), ErrNoMatchingKey)
+}
+
+func TestVerifyECDSACurves(t *testing.T) {
+	message := []byte("educates binary")
+	for _, tt := range []struct {
+		curve elliptic.Curve
+		hash  crypto.Hash
+	}{
+		{elliptic.P256(), crypto.SHA256},
+		{elliptic.P384(), crypto.SHA384},
+		{elliptic.P521(), crypto.SHA512},
+	} {
+		name := tt.curve.Params().Name
+		priv, err := ecdsa.GenerateKey(tt.curve, rand.Reader)
+		assert.NoError(t, err)
+		h := tt.hash.New()
+		h.Write(message)
+		sig, err := ecdsa.SignASN1(rand.Reader, priv, h.Sum(nil))
+		assert.NoError(t, err)
+		encoded := []byte(base64.StdEncoding.EncodeToString(sig))
+
+		v, err := NewVerifier([]string{pemPublicKey(t, &priv.PublicKey)})
+		assert.NoError(t, err)
+		assert.NoError(t, v.Verify(CosignSuffix, message, encoded), name)
+		assert.ErrorIs(t, v.Verify(CosignSuffix, []byte("tampered"), encoded), ErrNoMatchingKey, name)
+	}
+}
+
+func TestVerifyEd25519PEM(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+
+	message := []byte("checksums")
+	sig := ed25519.Sign(priv, message)
+
+	v, err := NewVerifier([]string{pemPublicKey(t, pub)})
+	assert.NoError(t, err)
+	assert.NoError(t, v.Verify(CosignSuffix, message, []byte(base64.StdEncoding.EncodeToString(sig))))
+}
+
+// minisignSign creates a minisign key and signature in the formats written by minisign
+func minisignSign(t *testing.T, message []byte, algorithm string) (string, []byte) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+	keyID := []byte{1, 2, 3, 4, 5, 6, 7, 8}
+
+	key := "untrusted comment: minisign public key 0807060504030201\n" +
+		base64.StdEncoding.EncodeToString(append(append([]byte("Ed"), keyID...), pub...)) + "\n"
+
+	signed := message
+	if algorithm == minisignHashedAlgorithm {
+		digest := blake2b.Sum512(message)
+		signed = digest[:]
+	}
+	sig := ed25519.Sign(priv, signed)
+	trustedComment := "timestamp:1700000000\tfile:educates-linux-amd64"
+	globalSig := ed25519.Sign(priv, append(append([]byte{}, sig...), trustedComment...))
+
+	signature := "untrusted comment: signature from minisign secret key\n" +
+		base64.StdEncoding.EncodeToString(append(append([]byte(algorithm), keyID...), sig...)) + "\n" +
+		trustedCommentPrefix + trustedComment + "\n" +
+		base64.StdEncoding.EncodeToString(globalSig) + "\n"
+	return key, []byte(signature)
+}
+
+func TestVerifyMinisign(t *testing.T) {
+	message := []byte("educates binary")
+
+	for _, algorithm := range []string{minisignAlgorithm, minisignHashedAlgorithm} {
+		t.Run(algorithm, func(t *testing.T) {
+			key, signature := minisignSign(t, message, algorithm)
+
+			v, err := NewVerifier([]string{key})
+			assert.NoError(t, err)
+			assert.NoError(t, v.Verify(MinisignSuffix, message, signature))
+			assert.Error(t, v.Verify(MinisignSuffix, []byte("tampered"), signature))
+
+			// The trusted comment is covered by the signature too
+			tampered := []byte(replaceLine(string(signature), 2, trustedCommentPrefix+"forged"))
+			assert.Error(t, v.Verify(MinisignSuffix, message, tampered))
+		})
+	}
+
+	// The bare base64 key line is accepted as well
+	key, signature := minisignSign(t, message, minisignHashedAlgorithm)
+	v, err := NewVerifier([]string{strings.Split(key, "\n")[1]})
+	assert.NoError(t, err)
+	assert.NoError(t, v.Verify(MinisignSuffix, message, signature))
+}
+
+func TestNewVerifierInvalidKey(t *testing.T) {
+	_, err := NewVerifier([]string{"/does/not/exist.pub"})
+	assert.Error(t, err)
+
+	keyFile := filepath.Join(t.TempDir(), "garbage.pub")
+	assert.NoError(t, os.WriteFile(keyFile, []byte("garbage"), 0o644))
+	_, err = NewVerifier([]string{keyFile})
+	assert.Error(t, err)
+}
+
+func TestLookupChecksum(t *testing.T) {
+	checksums := []byte("aaaa  educates-darwin-arm64\nBBBB *educates-linux-amd64\nmalformed line here\n")
+
+	digest, ok := LookupChecksum(checksums, "educates-linux-amd64")
+	assert.True(t, ok)
+	assert.Equal(t, "bbbb", digest)
+
+	_, ok = LookupChecksum(checksums, "educates-windows-amd64.exe")
+	assert.False(t, ok)
+}
+
+// replaceLine replaces the line at index i of text
+func replaceLine(text string, i int, line string) string {
+	lines := strings.Split(text, "\n")
+	lines[i] = line
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,244 @@
+package version
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/afero"
+
+	"github.com/educates/educatesenv/pkg/platform"
+)
+
+// activeMarkerName is the file that records which binary the active link was copied or
+// hard linked from, for platforms where a symlink could not be created
+const activeMarkerName = ".educates-active"
+
+// errSymlinkUnsupported is returned when the filesystem cannot create symlinks
+var errSymlinkUnsupported = errors.New("symlinks are not supported by this filesystem")
+
+// hardLinker is implemented by filesystems that can create hard links
+type hardLinker interface {
+	Link(oldname, newname string) error
+}
+
+// activate makes target run the binary at source. A relative symlink is preferred. On
+// Windows, where creating symlinks requires Developer Mode or administrator rights, a hard
+// link is tried next and a copy of the binary is the last resort. The new link is created
+// under a temporary name and renamed over target, so target is never missing.
+func (m *Manager) activate(source, target string) error {
+	// Check if source exists
+	if _, err := m.fs.Stat(source); err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("binary not found at %s", source)
+		}
+		return fmt.Errorf("failed to check binary: %w", err)
+	}
+
+	if err := m.checkReplaceable(target); err != nil {
+		return err
+	}
+
+	tmpPath := filepath.Join(filepath.Dir(target), fmt.Sprintf(".%s.%d.tmp", filepath.Base(target), os.Getpid()))
+	if err := m.fs.Remove(tmpPath); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove stale temporary link %s: %w", tmpPath, err)
+	}
+
+	// Create new symlink
+	relTarget, err := filepath.Rel(filepath.Dir(target), source)
+	if err != nil {
+		relTarget = source // fallback to absolute path
+	}
+	if m.portableRoot != "" {
+		relTarget = m.linkTarget(source, filepath.Dir(target))
+	}
+	symlinkErr := m.symlink(relTarget, tmpPath)
+	if symlinkErr == nil {
+		if err := m.replace(tmpPath, target); err != nil {
+			return err
+		}
+		return m.removeActiveMarker()
+	}
+	if m.goos != platform.Windows {
+		return fmt.Errorf("failed to create symlink: %w", symlinkErr)
+	}
+
+	absSource, err := filepath.Abs(source)
+	if err != nil {
+		absSource = source
+	}
+	if linker, ok := m.fs.(hardLinker); !ok || linker.Link(absSource, tmpPath) != nil {
+		if err := m.copyFile(absSource, tmpPath); err != nil {
+			_ = m.fs.Remove(tmpPath)
+			return fmt.Errorf("failed to activate %s (symlink: %v): %w", source, symlinkErr, err)
+		}
+	}
+	if err := afero.WriteFile(m.fs, m.activeMarkerPath(), []byte(absSource+"\n"), 0o644); err != nil {
+		_ = m.fs.Remove(tmpPath)
+		return fmt.Errorf("failed to write active version marker: %w", err)
+	}
+	return m.replace(tmpPath, target)
+}
+
+// replace renames tmpPath over target, removing tmpPath if that fails
+func (m *Manager) replace(tmpPath, target string) error {
+	if err := m.fs.Rename(tmpPath, target); err != nil {
+		_ = m.fs.Remove(tmpPath)
+		return fmt.Errorf("failed to replace %s: %w", target, err)
+	}
+	return nil
+}
+
+// deactivate removes the link to the active version
+func (m *Manager) deactivate() error {
+	if err := m.removeActive(m.linkPath()); err != nil {
+		return err
+	}
+	return m.removeActiveMarker()
+}
+
+// activeTarget returns the absolute path of the binary the active link runs, or an empty
+// string if no version is active
+func (m *Manager) activeTarget() (string, error) {
+	linkPath := m.linkPath()
+	fi, err := m.lstat(linkPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to check symlink: %w", err)
+	}
+
+	var target string
+	if fi.Mode()&os.ModeSymlink != 0 {
+		reader, ok := m.fs.(afero.LinkReader)
+		if !ok {
+			return "", errSymlinkUnsupported
+		}
+		target, err = reader.ReadlinkIfPossible(linkPath)
+		if err != nil {
+			return "", fmt.Errorf("failed to read symlink: %w", err)
+		}
+	} else {
+		data, err := afero.ReadFile(m.fs, m.activeMarkerPath())
+		if err != nil {
+			if os.IsNotExist(err) {
+				return "", nil
+			}
+			return "", fmt.Errorf("failed to read active version marker: %w", err)
+		}
+		target = strings.TrimSpace(string(data))
+	}
+
+	// Resolve relative symlink if needed
+	if !filepath.IsAbs(target) {
+		target = filepath.Join(filepath.Dir(linkPath), target)
+	}
+	return filepath.Clean(target), nil
+}
+
+// checkReplaceable returns an error if target exists and was not created by educatesenv.
+// Regular files are only replaced if they were created by activate as a hard link or copy.
+func (m *Manager) checkReplaceable(target string) error {
+	fi, err := m.lstat(target)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to check symlink: %w", err)
+	}
+
+	if fi.Mode()&os.ModeSymlink == 0 {
+		if _, err := m.fs.Stat(m.activeMarkerPath()); err != nil {
+			return fmt.Errorf("%s exists and is not a symlink", target)
+		}
+	}
+	return nil
+}
+
+// removeActive removes an existing link at target
+func (m *Manager) removeActive(target string) error {
+	if err := m.checkReplaceable(target); err != nil {
+		return err
+	}
+	if err := m.fs.Remove(target); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove existing symlink: %w", err)
+	}
+	return nil
+}
+
+// removeActiveMarker removes the marker written by activate when it could not symlink
+func (m *Manager) removeActiveMarker() error {
+	if err := m.fs.Remove(m.activeMarkerPath()); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove active version marker: %w", err)
+	}
+	return nil
+}
+
+// activeMarkerPath returns the path of the active version marker
+func (m *Manager) activeMarkerPath() string {
+	return filepath.Join(m.config.Local.Dir, activeMarkerName)
+}
+
+// linkTarget returns the target of a link in dir to source. In portable mode, a source
+// inside the tree is made relative, so that the link keeps working when the tree is moved,
+// and one outside it is kept absolute.
+func (m *Manager) linkTarget(source, dir string) string {
+	if m.portableRoot == "" {
+		return source
+	}
+	if rel, err := filepath.Rel(m.portableRoot, source); err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return source
+	}
+	if rel, err := filepath.Rel(dir, source); err == nil {
+		return rel
+	}
+	return source
+}
+
+// symlink creates newname as a symlink to oldname if the filesystem supports it
+func (m *Manager) symlink(oldname, newname string) error {
+	linker, ok := m.fs.(afero.Linker)
+	if !ok {
+		return errSymlinkUnsupported
+	}
+	return linker.SymlinkIfPossible(oldname, newname)
+}
+
+// lstat returns file info without following symlinks if the filesystem supports it
+func (m *Manager) lstat(name string) (os.FileInfo, error) {
+	if lstater, ok := m.fs.(afero.Lstater); ok {
+		fi, _, err := lstater.LstatIfPossible(name)
+		return fi, err
+	}
+	return m.fs.Stat(name)
+}
+
+// copyFile copies source to target, preserving the executable bit
+func (m *Manager) copyFile(source, target string) (err error) {
+	in, err := m.fs.Open(source)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := in.Close(); cerr != nil && err == nil {
+			err = fmt.Errorf("error closing source file: %w", cerr)
+		}
+	}()
+
+	out, err := m.fs.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o755)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := out.Close(); cerr != nil && err == nil {
+			err = fmt.Errorf("error closing output file: %w", cerr)
+		}
+	}()
+
+	_, err = io.Copy(out, in)
+	return err
+}
@@ -0,0 +1,173 @@
+package version
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+
+	"github.com/spf13/afero"
+	"gopkg.in/yaml.v3"
+
+	"github.com/educates/educatesenv/pkg/semver"
+)
+
+// aliasNamePattern restricts alias names to ones that cannot be mistaken for flags or paths
+var aliasNamePattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9._-]*$`)
+
+// Alias is a name for a version or version constraint
+type Alias struct {
+	Name   string
+	Target string
+}
+
+// aliasFile is the on-disk format of the aliases file
+type aliasFile struct {
+	Aliases map[string]string `yaml:"aliases"`
+}
+
+// Aliases returns all aliases sorted by name
+func (m *Manager) Aliases() ([]Alias, error) {
+	aliases, err := m.loadAliases()
+	if err != nil {
+		return nil, err
+	}
+	var result []Alias
+	for name, target := range aliases {
+		result = append(result, Alias{Name: name, Target: target})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result, nil
+}
+
+// SetAlias points an alias at a version, a constraint or a development build
+func (m *Manager) SetAlias(name, target string) error {
+	if err := validateAliasName(name); err != nil {
+		return err
+	}
+	if !IsDevVersion(target) {
+		if _, err := semver.ParseConstraint(target); err != nil {
+			return fmt.Errorf("alias target must be a version, a constraint or a development build: %w", err)
+		}
+	}
+
+	unlock, err := m.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	aliases, err := m.loadAliases()
+	if err != nil {
+		return err
+	}
+	aliases[name] = target
+	return m.saveAliases(aliases)
+}
+
+// RemoveAlias removes an alias
+func (m *Manager) RemoveAlias(name string) error {
+	unlock, err := m.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	aliases, err := m.loadAliases()
+	if err != nil {
+		return err
+	}
+	if _, ok := aliases[name]; !ok {
+		return fmt.Errorf("alias %s does not exist", name)
+	}
+	delete(aliases, name)
+	return m.saveAliases(aliases)
+}
+
+// ResolveAlias returns the target of spec if it is an alias, or spec itself otherwise
+func (m *Manager) ResolveAlias(spec string) (string, error) {
+	aliases, err := m.loadAliases()
+	if err != nil {
+		return "", err
+	}
+	if target, ok := aliases[spec]; ok {
+		return target, nil
+	}
+	return spec, nil
+}
+
+// AliasesByVersion returns the names of the aliases that resolve to each installed version
+// or development build
+func (m *Manager) AliasesByVersion() (map[string][]string, error) {
+	aliases, err := m.Aliases()
+	if err != nil {
+		return nil, err
+	}
+	installed, err := m.InstalledVersions()
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := map[string][]string{}
+	for _, alias := range aliases {
+		version := alias.Target
+		if !IsDevVersion(version) {
+			var ok bool
+			if version, ok, err = MatchVersion(alias.Target, installed); err != nil || !ok {
+				continue
+			}
+		}
+		byVersion[version] = append(byVersion[version], alias.Name)
+	}
+	return byVersion, nil
+}
+
+// validateAliasName rejects names that could not be told apart from a version
+func validateAliasName(name string) error {
+	if !aliasNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid alias name %q: use letters, digits, '.', '_' and '-', starting with a letter", name)
+	}
+	if name == DevVersion {
+		return fmt.Errorf("invalid alias name %q: reserved for the development version", name)
+	}
+	if _, err := semver.ParseConstraint(name); err == nil {
+		return fmt.Errorf("invalid alias name %q: it would be read as a version", name)
+	}
+	return nil
+}
+
+// loadAliases reads the aliases file, returning no aliases if it does not exist
+func (m *Manager) loadAliases() (map[string]string, error) {
+	file := aliasFile{}
+	data, err := afero.ReadFile(m.fs, m.aliasesPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return map[string]string{}, nil
+		}
+		return nil, fmt.Errorf("failed to read aliases: %w", err)
+	}
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse aliases file %s: %w", m.aliasesPath, err)
+	}
+	if file.Aliases == nil {
+		file.Aliases = map[string]string{}
+	}
+	return file.Aliases, nil
+}
+
+// saveAliases writes the aliases file atomically. The caller must hold the lock.
+func (m *Manager) saveAliases(aliases map[string]string) error {
+	data, err := yaml.Marshal(&aliasFile{Aliases: aliases})
+	if err != nil {
+		return fmt.Errorf("failed to encode aliases: %w", err)
+	}
+	tmpPath := m.aliasesPath + ".tmp"
+	if err := afero.WriteFile(m.fs, tmpPath, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write aliases: %w", err)
+	}
+	if err := m.fs.Rename(tmpPath, m.aliasesPath); err != nil {
+		_ = m.fs.Remove(tmpPath)
+		return fmt.Errorf("failed to write aliases: %w", err)
+	}
+	return nil
+}
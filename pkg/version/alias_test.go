@@ -0,0 +1,63 @@
+package version
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAliases(t *testing.T) {
+	manager, tmpDir, cleanup := setupTestManager(t)
+	defer cleanup()
+
+	for _, version := range []string{"3.1.4", "3.2.0", "3.2.3"} {
+		err := os.WriteFile(filepath.Join(tmpDir, "educates-"+version), []byte("test binary"), 0o755)
+		assert.NoError(t, err)
+	}
+
+	assert.NoError(t, manager.SetAlias("kubecon", "3.1.4"))
+	assert.NoError(t, manager.SetAlias("stable-for-training", "~3.2"))
+	assert.NoError(t, manager.SetAlias("next", "4"))
+
+	aliases, err := manager.Aliases()
+	assert.NoError(t, err)
+	assert.Equal(t, []Alias{
+		{Name: "kubecon", Target: "3.1.4"},
+		{Name: "next", Target: "4"},
+		{Name: "stable-for-training", Target: "~3.2"},
+	}, aliases)
+
+	version, ok, err := manager.ResolveInstalled("stable-for-training")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "3.2.3", version)
+
+	byVersion, err := manager.AliasesByVersion()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]string{"3.1.4": {"kubecon"}, "3.2.3": {"stable-for-training"}}, byVersion)
+
+	// Aliases can be repointed and removed
+	assert.NoError(t, manager.SetAlias("kubecon", "3.2.0"))
+	target, err := manager.ResolveAlias("kubecon")
+	assert.NoError(t, err)
+	assert.Equal(t, "3.2.0", target)
+
+	assert.NoError(t, manager.RemoveAlias("kubecon"))
+	assert.Error(t, manager.RemoveAlias("kubecon"))
+	target, err = manager.ResolveAlias("kubecon")
+	assert.NoError(t, err)
+	assert.Equal(t, "kubecon", target)
+}
+
+func TestSetAliasInvalid(t *testing.T) {
+	manager, _, cleanup := setupTestManager(t)
+	defer cleanup()
+
+	for _, name := range []string{"3.2", "v3", "develop", "-stable", "with space"} {
+		assert.Error(t, manager.SetAlias(name, "3.2.0"), name)
+	}
+	assert.Error(t, manager.SetAlias("stable", "latest"))
+	assert.NoError(t, manager.SetAlias("dev", "develop"))
+}
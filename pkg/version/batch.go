@@ -0,0 +1,155 @@
+package version
+
+import (
+	"fmt"
+	"sync"
+)
+
+// DefaultInstallWorkers is the number of versions InstallVersions downloads at once by default
+const DefaultInstallWorkers = 4
+
+// Outcomes of installing a version with InstallVersions
+const (
+	InstallInstalled   = "installed"
+	InstallReinstalled = "reinstalled"
+	InstallSkipped     = "already installed"
+	InstallFailed      = "failed"
+)
+
+// InstallResult is the outcome of installing one version with InstallVersions
+type InstallResult struct {
+	Version string
+	Status  string
+	Err     error
+}
+
+// InstallVersions installs several versions, downloading up to workers of them at once.
+// A failure to install one version does not stop the others. Progress messages are
+// passed to report along with the version they concern, and may come from several
+// goroutines at once. The results are returned in the order of versions.
+func (m *Manager) InstallVersions(versions []string, force bool, workers int, report func(version, message string)) ([]InstallResult, error) {
+	unlock, err := m.lock()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	binDir := m.config.Local.Dir
+	if err := m.fs.MkdirAll(binDir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create bin directory %s: %w", binDir, err)
+	}
+
+	// Look up the assets of every release to download in a single listing of the releases.
+	// Releases beyond the listing are looked up one at a time, as is everything if the
+	// listing fails, so that its error is reported for each version.
+	var releaseAssets map[string]map[string]string
+	for _, version := range versions {
+		if force || !m.IsInstalled(version) {
+			releaseAssets, _ = m.github.ListReleaseAssets()
+			break
+		}
+	}
+
+	// Hooks and the install registry are not safe to use concurrently, so everything but
+	// looking up and downloading the binary is done holding mu
+	var mu sync.Mutex
+	results := make([]InstallResult, len(versions))
+	runPool(len(versions), workers, func(i int) {
+		version := versions[i]
+		results[i] = InstallResult{Version: version}
+		status, err := m.installOne(version, force, releaseAssets[version], &mu, func(message string) { report(version, message) })
+		if err != nil {
+			results[i].Status, results[i].Err = InstallFailed, err
+			report(version, fmt.Sprintf("Failed: %v", err))
+			return
+		}
+		results[i].Status = status
+	})
+
+	var installed []string
+	for _, result := range results {
+		if result.Status == InstallInstalled || result.Status == InstallReinstalled {
+			installed = append(installed, result.Version)
+		}
+	}
+	if len(installed) > 0 {
+		m.warnAutoPrune(installed...)
+	}
+	return results, nil
+}
+
+// installOne installs a version for InstallVersions from assets, the assets of its release,
+// which are looked up if nil. It holds mu for all but looking up and downloading the binary.
+func (m *Manager) installOne(version string, force bool, assets map[string]string, mu *sync.Mutex, report func(string)) (string, error) {
+	mu.Lock()
+	exists := m.IsInstalled(version)
+	if exists && !force {
+		err := m.checkPinned(version)
+		mu.Unlock()
+		if err != nil {
+			return "", err
+		}
+		report("Already installed.")
+		return InstallSkipped, nil
+	}
+	mu.Unlock()
+
+	if assets == nil {
+		var err error
+		if assets, err = m.github.GetReleaseAssets(version); err != nil {
+			return "", err
+		}
+	}
+
+	mu.Lock()
+	dl, err := m.prepareDownload(version, exists, assets)
+	if err == nil {
+		err = m.runHooks(HookPreInstall, m.config.Hooks.PreInstall, dl.hooks)
+		if err != nil {
+			err = fmt.Errorf("aborting install of %s: %w", version, err)
+		}
+	}
+	mu.Unlock()
+	if err != nil {
+		return "", err
+	}
+
+	if err := m.download(dl, report); err != nil {
+		return "", err
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if err := m.completeDownload(dl); err != nil {
+		return "", err
+	}
+	report("Installed successfully.")
+	m.runPostHooks(HookPostInstall, m.config.Hooks.PostInstall, dl.hooks)
+	if exists {
+		return InstallReinstalled, nil
+	}
+	return InstallInstalled, nil
+}
+
+// runPool calls fn for each index below n, from at most workers goroutines at once
+func runPool(n, workers int, fn func(i int)) {
+	if workers < 1 {
+		workers = 1
+	}
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < min(workers, n); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				fn(i)
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+}
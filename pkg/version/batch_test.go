@@ -0,0 +1,117 @@
+package version
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/educates/educatesenv/pkg/github"
+	"github.com/educates/educatesenv/pkg/platform"
+)
+
+func TestRunPool(t *testing.T) {
+	var running, peak atomic.Int32
+	var mu sync.Mutex
+	seen := map[int]bool{}
+	runPool(10, 3, func(i int) {
+		n := running.Add(1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		running.Add(-1)
+
+		mu.Lock()
+		seen[i] = true
+		mu.Unlock()
+	})
+
+	assert.Len(t, seen, 10)
+	assert.LessOrEqual(t, peak.Load(), int32(3))
+
+	// No work, and fewer items than workers, are handled too
+	runPool(0, 3, func(int) { t.Fatal("called with no items") })
+	calls := 0
+	runPool(1, 0, func(int) { calls++ })
+	assert.Equal(t, 1, calls)
+}
+
+func TestInstallVersionsAlreadyInstalled(t *testing.T) {
+	manager, _, cleanup := setupTestManager(t)
+	defer cleanup()
+
+	for _, v := range []string{"3.1.0", "3.2.0"} {
+		assert.NoError(t, os.WriteFile(manager.binaryPath(v), []byte("binary"), 0o755))
+	}
+
+	var mu sync.Mutex
+	messages := map[string][]string{}
+	results, err := manager.InstallVersions([]string{"3.2.0", "3.1.0"}, false, 2, func(version, message string) {
+		mu.Lock()
+		defer mu.Unlock()
+		messages[version] = append(messages[version], message)
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []InstallResult{
+		{Version: "3.2.0", Status: InstallSkipped},
+		{Version: "3.1.0", Status: InstallSkipped},
+	}, results)
+	assert.Equal(t, []string{"Already installed."}, messages["3.1.0"])
+}
+
+func TestInstallVersionsPartialFailure(t *testing.T) {
+	manager, _, cleanup := setupTestManager(t)
+	defer cleanup()
+
+	assetName := platform.GetPlatformBinaryName(manager.goos, manager.goarch)
+	var listings, lookups atomic.Int32
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v3/repos/testorg/testrepo/releases":
+			listings.Add(1)
+			release := func(tag, url string) map[string]any {
+				return map[string]any{"tag_name": tag, "assets": []map[string]any{{"name": assetName, "url": url}}}
+			}
+			_ = json.NewEncoder(w).Encode([]map[string]any{
+				release("3.2.0", server.URL+"/download/3.2.0"),
+				release("3.1.0", server.URL+"/download/missing"),
+			})
+		case "/download/3.2.0":
+			_, _ = w.Write([]byte("binary"))
+		default:
+			if r.URL.Path == "/api/v3/repos/testorg/testrepo/releases/tags/3.0.0" {
+				lookups.Add(1)
+			}
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	manager.config.Github.BaseURL = server.URL + "/"
+	manager.github = github.New(manager.config)
+
+	results, err := manager.InstallVersions([]string{"3.2.0", "3.1.0", "3.0.0"}, false, 3, func(string, string) {})
+	assert.NoError(t, err)
+	assert.Len(t, results, 3)
+	assert.Equal(t, InstallResult{Version: "3.2.0", Status: InstallInstalled}, results[0])
+	assert.Equal(t, InstallFailed, results[1].Status)
+	assert.ErrorContains(t, results[1].Err, "404")
+	assert.Equal(t, InstallFailed, results[2].Status)
+	assert.ErrorContains(t, results[2].Err, "version 3.0.0 not found")
+
+	// The releases are listed once, and only the release missing from the listing is looked up
+	assert.Equal(t, int32(1), listings.Load())
+	assert.Equal(t, int32(1), lookups.Load())
+	assert.True(t, manager.IsInstalled("3.2.0"))
+	assert.False(t, manager.IsInstalled("3.1.0"))
+}
@@ -0,0 +1,258 @@
+package version
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/afero"
+
+	"github.com/educates/educatesenv/pkg/paths"
+	"github.com/educates/educatesenv/pkg/platform"
+)
+
+// buildInfoFileName is the file next to a managed development build that describes it
+const buildInfoFileName = "build.json"
+
+// invalidBuildNameChars matches the characters replaced when deriving a build name from a branch
+var invalidBuildNameChars = regexp.MustCompile(`[^a-z0-9_-]+`)
+
+// DevBuildOptions describes a development build to make from a source checkout
+type DevBuildOptions struct {
+	// Checkout is a directory in a git checkout of the educates repository
+	Checkout string
+	// Name is the build name, used as dev:<name>. It defaults to the checked out branch.
+	Name string
+	// Force rebuilds even if the commit has not changed
+	Force bool
+}
+
+// BuildInfo describes a development build made by BuildDev
+type BuildInfo struct {
+	Name     string    `json:"name"`
+	Checkout string    `json:"checkout"`
+	Commit   string    `json:"commit"`
+	Dirty    bool      `json:"dirty"`
+	BuiltAt  time.Time `json:"builtAt"`
+	Binary   string    `json:"binary"`
+}
+
+// Revision returns the abbreviated commit, marked if the checkout had uncommitted changes
+func (b *BuildInfo) Revision() string {
+	revision := b.Commit
+	if len(revision) > 12 {
+		revision = revision[:12]
+	}
+	if b.Dirty {
+		revision += "-dirty"
+	}
+	return revision
+}
+
+// BuildDev builds the educates client from a checkout into the managed development builds
+// directory. The build is skipped if the last build of the same name was made from the
+// same checkout and commit, and neither had uncommitted changes. It returns the build and
+// whether it was rebuilt. The lock is only taken to put the build in place, so that other
+// commands can run while it builds.
+func (m *Manager) BuildDev(opts DevBuildOptions) (*BuildInfo, bool, error) {
+	checkout, err := git(opts.Checkout, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, false, fmt.Errorf("%s is not a git checkout: %w", opts.Checkout, err)
+	}
+	commit, err := git(checkout, "rev-parse", "HEAD")
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to determine commit of %s: %w", checkout, err)
+	}
+	status, err := git(checkout, "status", "--porcelain")
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to determine state of %s: %w", checkout, err)
+	}
+
+	name := strings.TrimPrefix(opts.Name, DevBuildPrefix)
+	if name == "" {
+		branch, err := git(checkout, "rev-parse", "--abbrev-ref", "HEAD")
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to determine branch of %s: %w", checkout, err)
+		}
+		if name = buildNameFromBranch(branch); name == "" {
+			return nil, false, fmt.Errorf("cannot derive a build name from the checkout; pass --name")
+		}
+	}
+	if err := ValidateDevBuildName(name); err != nil {
+		return nil, false, err
+	}
+
+	info := &BuildInfo{
+		Name:     name,
+		Checkout: checkout,
+		Commit:   commit,
+		Dirty:    status != "",
+		Binary:   filepath.Join(m.devDir, name, platform.GetActiveBinaryName(m.goos)),
+	}
+
+	previous, err := m.DevBuildInfo(name)
+	if err != nil {
+		return nil, false, err
+	}
+	if !opts.Force && previous != nil && previous.Checkout == info.Checkout && previous.Commit == info.Commit && !previous.Dirty && !info.Dirty {
+		if _, err := m.fs.Stat(previous.Binary); err == nil {
+			return previous, false, nil
+		}
+	}
+
+	if err := m.fs.MkdirAll(filepath.Dir(info.Binary), 0o755); err != nil {
+		return nil, false, fmt.Errorf("failed to create build directory: %w", err)
+	}
+	// Build next to the binary and rename it into place, as it may be active and running.
+	// The name is unique to this process, as builds are made without holding the lock.
+	tmpPath := filepath.Join(filepath.Dir(info.Binary), fmt.Sprintf(".build-%d%s", os.Getpid(), platform.ExecutableExtension(m.goos)))
+	defer func() { _ = m.fs.Remove(tmpPath) }()
+
+	if err := m.runBuild(info, tmpPath); err != nil {
+		return nil, false, err
+	}
+	if _, err := m.fs.Stat(tmpPath); err != nil {
+		return nil, false, fmt.Errorf("build did not create %s", tmpPath)
+	}
+	if err := m.fs.Chmod(tmpPath, 0o755); err != nil {
+		return nil, false, fmt.Errorf("failed to make build executable: %w", err)
+	}
+
+	unlock, err := m.lock()
+	if err != nil {
+		return nil, false, err
+	}
+	defer unlock()
+	if err := m.fs.Rename(tmpPath, info.Binary); err != nil {
+		return nil, false, fmt.Errorf("failed to install build: %w", err)
+	}
+
+	info.BuiltAt = time.Now().UTC()
+	if err := m.saveBuildInfo(info); err != nil {
+		return nil, false, err
+	}
+	return info, true, nil
+}
+
+// DevBuildInfo returns the description of a managed development build, or nil if the
+// build was not made by BuildDev
+func (m *Manager) DevBuildInfo(name string) (*BuildInfo, error) {
+	path := filepath.Join(m.devDir, strings.TrimPrefix(name, DevBuildPrefix), buildInfoFileName)
+	data, err := afero.ReadFile(m.fs, path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read build info: %w", err)
+	}
+	info := &BuildInfo{}
+	if err := json.Unmarshal(data, info); err != nil {
+		return nil, fmt.Errorf("failed to parse build info %s: %w", path, err)
+	}
+	layout := m.portableLayout()
+	info.Binary = layout.Abs(info.Binary)
+	info.Checkout = layout.Abs(info.Checkout)
+	return info, nil
+}
+
+// RemoveDevBuild removes the files of a managed development build, if it was made by
+// BuildDev. It reports whether there were any.
+func (m *Manager) RemoveDevBuild(name string) (bool, error) {
+	unlock, err := m.lock()
+	if err != nil {
+		return false, err
+	}
+	defer unlock()
+
+	dir := filepath.Join(m.devDir, strings.TrimPrefix(name, DevBuildPrefix))
+	if _, err := m.fs.Stat(filepath.Join(dir, buildInfoFileName)); err != nil {
+		return false, nil
+	}
+	if err := m.fs.RemoveAll(dir); err != nil {
+		return false, fmt.Errorf("failed to remove build directory: %w", err)
+	}
+	return true, nil
+}
+
+// runBuild runs the configured build command, or go build for the client-programs module,
+// writing the binary to output. Modules are only taken from the module cache and the
+// local Go toolchain is used.
+func (m *Manager) runBuild(info *BuildInfo, output string) error {
+	var cmd *exec.Cmd
+	if m.config.Development.BuildCommand == "" {
+		cmd = exec.Command("go", "build", "-o", output, "./cmd/educates")
+		cmd.Dir = filepath.Join(info.Checkout, "client-programs")
+	} else {
+		cmd = shellCommand(m.config.Development.BuildCommand)
+		cmd.Dir = info.Checkout
+	}
+	cmd.Env = append(os.Environ(),
+		"GOPROXY=off",
+		"GOTOOLCHAIN=local",
+		"EDUCATESENV_BUILD_OUTPUT="+output,
+		"EDUCATESENV_BUILD_CHECKOUT="+info.Checkout,
+		"EDUCATESENV_BUILD_COMMIT="+info.Commit,
+		"EDUCATESENV_BUILD_DIRTY="+strconv.FormatBool(info.Dirty),
+	)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("build failed: %w", err)
+	}
+	return nil
+}
+
+// saveBuildInfo writes the description of a managed development build. Paths within a
+// portable tree are stored relative to it. The caller must hold the lock.
+func (m *Manager) saveBuildInfo(info *BuildInfo) error {
+	layout := m.portableLayout()
+	stored := *info
+	stored.Binary = layout.Rel(info.Binary)
+	stored.Checkout = layout.Rel(info.Checkout)
+	data, err := json.MarshalIndent(&stored, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode build info: %w", err)
+	}
+	path := filepath.Join(filepath.Dir(info.Binary), buildInfoFileName)
+	if err := afero.WriteFile(m.fs, path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write build info: %w", err)
+	}
+	return nil
+}
+
+// portableLayout returns the layout used to store paths relative to the portable tree,
+// which leaves them unchanged outside portable mode
+func (m *Manager) portableLayout() paths.Layout {
+	return paths.Layout{Config: m.portableRoot, Portable: m.portableRoot != ""}
+}
+
+// buildNameFromBranch derives a build name from a branch, such as feature-x from
+// feature/X. A detached HEAD has no name.
+func buildNameFromBranch(branch string) string {
+	if branch == "HEAD" {
+		return ""
+	}
+	return strings.Trim(invalidBuildNameChars.ReplaceAllString(strings.ToLower(branch), "-"), "-_")
+}
+
+// git runs a git command in dir and returns its trimmed output
+func git(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("%s", msg)
+		}
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
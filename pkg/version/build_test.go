@@ -0,0 +1,147 @@
+package version
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// initCheckout creates a git repository with one commit on the given branch
+func initCheckout(t *testing.T, branch string) string {
+	dir := t.TempDir()
+	for _, args := range [][]string{
+		{"init", "-q", "-b", branch},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "--allow-empty", "-m", "initial"},
+	} {
+		out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput()
+		assert.NoError(t, err, string(out))
+	}
+	return dir
+}
+
+func TestBuildDev(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the build command in this test uses sh")
+	}
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	manager, tmpDir, cleanup := setupTestManager(t)
+	defer cleanup()
+
+	checkout := initCheckout(t, "feature/X")
+	buildLog := filepath.Join(tmpDir, "builds.log")
+	manager.config.Development.BuildCommand = `echo "$EDUCATESENV_BUILD_COMMIT $EDUCATESENV_BUILD_DIRTY $GOPROXY" >> ` + buildLog + ` && echo binary > "$EDUCATESENV_BUILD_OUTPUT"`
+
+	info, rebuilt, err := manager.BuildDev(DevBuildOptions{Checkout: checkout})
+	assert.NoError(t, err)
+	assert.True(t, rebuilt)
+	assert.Equal(t, "feature-x", info.Name)
+	assert.False(t, info.Dirty)
+	assert.Equal(t, filepath.Join(tmpDir, "dev", "feature-x", "educates"), info.Binary)
+	data, err := os.ReadFile(info.Binary)
+	assert.NoError(t, err)
+	assert.Equal(t, "binary\n", string(data))
+
+	// The same commit is not rebuilt
+	_, rebuilt, err = manager.BuildDev(DevBuildOptions{Checkout: checkout})
+	assert.NoError(t, err)
+	assert.False(t, rebuilt)
+
+	// Uncommitted changes are always rebuilt, and recorded
+	assert.NoError(t, os.WriteFile(filepath.Join(checkout, "change.txt"), []byte("change"), 0o644))
+	info, rebuilt, err = manager.BuildDev(DevBuildOptions{Checkout: checkout, Name: "dev:wip"})
+	assert.NoError(t, err)
+	assert.True(t, rebuilt)
+	assert.Equal(t, "wip", info.Name)
+	assert.True(t, info.Dirty)
+	assert.True(t, strings.HasSuffix(info.Revision(), "-dirty"))
+
+	saved, err := manager.DevBuildInfo("dev:wip")
+	assert.NoError(t, err)
+	assert.Equal(t, info.Commit, saved.Commit)
+
+	logged, err := os.ReadFile(buildLog)
+	assert.NoError(t, err)
+	assert.Equal(t, info.Commit+" false off\n"+info.Commit+" true off\n", string(logged))
+
+	_, _, err = manager.BuildDev(DevBuildOptions{Checkout: tmpDir})
+	assert.Error(t, err)
+
+	removed, err := manager.RemoveDevBuild("wip")
+	assert.NoError(t, err)
+	assert.True(t, removed)
+	_, err = os.Stat(info.Binary)
+	assert.True(t, os.IsNotExist(err))
+	removed, err = manager.RemoveDevBuild("wip")
+	assert.NoError(t, err)
+	assert.False(t, removed)
+}
+
+func TestBuildDevDoesNotHoldLock(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the build command in this test uses sh")
+	}
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	manager, tmpDir, cleanup := setupTestManager(t)
+	defer cleanup()
+	manager.lockTimeout = 5 * time.Second
+
+	checkout := initCheckout(t, "main")
+	started, done := filepath.Join(tmpDir, "started"), filepath.Join(tmpDir, "done")
+	manager.config.Development.BuildCommand = `touch ` + started + ` && while [ ! -f ` + done + ` ]; do sleep 0.05; done && echo binary > "$EDUCATESENV_BUILD_OUTPUT"`
+
+	result := make(chan error, 1)
+	go func() {
+		_, _, err := manager.BuildDev(DevBuildOptions{Checkout: checkout})
+		result <- err
+	}()
+	assert.Eventually(t, func() bool {
+		_, err := os.Stat(started)
+		return err == nil
+	}, 10*time.Second, 10*time.Millisecond)
+
+	// Other commands can take the lock while the build runs
+	unlock, err := manager.lock()
+	assert.NoError(t, err)
+	unlock()
+
+	assert.NoError(t, os.WriteFile(done, nil, 0o644))
+	assert.NoError(t, <-result)
+	assert.FileExists(t, filepath.Join(tmpDir, "dev", "main", "educates"))
+}
+
+func TestBuildInfoPortable(t *testing.T) {
+	manager, tmpDir, cleanup := setupTestManager(t)
+	defer cleanup()
+	manager.portableRoot = tmpDir
+
+	checkout := filepath.Join(tmpDir, "src", "educates")
+	binary := filepath.Join(tmpDir, "dev", "main", "educates")
+	assert.NoError(t, os.MkdirAll(filepath.Dir(binary), 0o755))
+	assert.NoError(t, manager.saveBuildInfo(&BuildInfo{Name: "main", Checkout: checkout, Binary: binary}))
+
+	// Paths within the tree are stored relative to it
+	data, err := os.ReadFile(filepath.Join(filepath.Dir(binary), buildInfoFileName))
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `"binary": "`+filepath.ToSlash(filepath.Join("dev", "main", "educates"))+`"`)
+
+	// and resolved against wherever the tree is now
+	moved := filepath.Join(t.TempDir(), "moved")
+	assert.NoError(t, os.Rename(tmpDir, moved))
+	defer os.Rename(moved, tmpDir)
+	manager.portableRoot = moved
+	manager.devDir = filepath.Join(moved, "dev")
+	info, err := manager.DevBuildInfo("dev:main")
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(moved, "dev", "main", "educates"), info.Binary)
+	assert.Equal(t, filepath.Join(moved, "src", "educates"), info.Checkout)
+}
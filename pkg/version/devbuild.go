@@ -0,0 +1,99 @@
+package version
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// DevVersion is the version name of the default development build, set by
+// development.binaryLocation
+const DevVersion = "develop"
+
+// DevBuildPrefix prefixes the version names of the named development builds
+const DevBuildPrefix = "dev:"
+
+// devBuildNamePattern restricts build names to ones that survive as config keys
+var devBuildNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// DevBuild is a development binary that can be used like an installed version
+type DevBuild struct {
+	// Version is "develop" or dev:<name>
+	Version string
+	Path    string
+}
+
+// IsDevVersion reports whether version names a development build rather than a release
+func IsDevVersion(version string) bool {
+	return version == DevVersion || strings.HasPrefix(version, DevBuildPrefix)
+}
+
+// DevBuildVersion returns the version name of the named development build. A name that
+// already carries the dev: prefix is returned as is.
+func DevBuildVersion(name string) string {
+	if strings.HasPrefix(name, DevBuildPrefix) {
+		return name
+	}
+	return DevBuildPrefix + name
+}
+
+// ValidateDevBuildName checks the name of a development build, with or without its dev:
+// prefix
+func ValidateDevBuildName(name string) error {
+	name = strings.TrimPrefix(name, DevBuildPrefix)
+	if !devBuildNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid development build name %q: use lowercase letters, digits, '_' and '-'", name)
+	}
+	return nil
+}
+
+// DevBuilds returns the configured development builds, with develop first followed by the
+// named builds sorted by name. None are returned if development mode is disabled.
+func (m *Manager) DevBuilds() []DevBuild {
+	if !m.config.Development.Enabled {
+		return nil
+	}
+	var builds []DevBuild
+	if m.config.Development.BinaryLocation != "" {
+		builds = append(builds, DevBuild{Version: DevVersion, Path: m.config.Development.BinaryLocation})
+	}
+	var named []DevBuild
+	for name, path := range m.config.Development.Builds {
+		named = append(named, DevBuild{Version: DevBuildPrefix + name, Path: path})
+	}
+	sort.Slice(named, func(i, j int) bool { return named[i].Version < named[j].Version })
+	return append(builds, named...)
+}
+
+// devBinary returns the binary configured for a development build
+func (m *Manager) devBinary(version string) (string, error) {
+	if !m.config.Development.Enabled {
+		return "", fmt.Errorf("development mode is not enabled. Enable it in the config file by setting development.enabled to true")
+	}
+	if version == DevVersion {
+		if m.config.Development.BinaryLocation == "" {
+			return "", fmt.Errorf("development binary location is not set. Set development.binaryLocation in the config file")
+		}
+		return m.config.Development.BinaryLocation, nil
+	}
+
+	name := strings.TrimPrefix(version, DevBuildPrefix)
+	path, ok := m.config.Development.Builds[name]
+	if !ok || path == "" {
+		return "", fmt.Errorf("development build %s is not defined. Add it with `educatesenv dev add %s <path>`", name, name)
+	}
+	return path, nil
+}
+
+// activeDevBuild returns the development build the active link points to, if any
+func (m *Manager) activeDevBuild(target string) (string, bool) {
+	for _, build := range m.DevBuilds() {
+		path, err := filepath.Abs(build.Path)
+		if err == nil && path == target {
+			return build.Version, true
+		}
+	}
+	return "", false
+}
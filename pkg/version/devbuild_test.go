@@ -0,0 +1,64 @@
+package version
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUseDevBuilds(t *testing.T) {
+	manager, tmpDir, cleanup := setupTestManager(t)
+	defer cleanup()
+
+	srcDir := t.TempDir()
+	builds := map[string]string{}
+	for _, name := range []string{"develop", "main", "feature-x"} {
+		path := filepath.Join(srcDir, name, "educates")
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		assert.NoError(t, os.WriteFile(path, []byte(name), 0o755))
+		builds[name] = path
+	}
+	manager.config.Development.Enabled = true
+	manager.config.Development.BinaryLocation = builds["develop"]
+	manager.config.Development.Builds = map[string]string{"main": builds["main"], "feature-x": builds["feature-x"]}
+
+	assert.Equal(t, []DevBuild{
+		{Version: "develop", Path: builds["develop"]},
+		{Version: "dev:feature-x", Path: builds["feature-x"]},
+		{Version: "dev:main", Path: builds["main"]},
+	}, manager.DevBuilds())
+
+	for _, version := range []string{"dev:main", "dev:feature-x", "develop"} {
+		assert.NoError(t, manager.UseVersion(version))
+		active, err := manager.ActiveVersion()
+		assert.NoError(t, err)
+		assert.Equal(t, version, active)
+	}
+
+	// Development builds are not recorded as used releases
+	reg, err := manager.loadRegistry()
+	assert.NoError(t, err)
+	assert.Empty(t, reg.LastUsed)
+
+	err = manager.UseVersion("dev:missing")
+	assert.ErrorContains(t, err, "development build missing is not defined")
+
+	// Disabling development mode removes the link to a named build too
+	assert.NoError(t, manager.UseVersion("dev:main"))
+	manager.config.Development.Enabled = false
+	assert.Error(t, manager.ValidateDevelopmentMode())
+	_, err = os.Lstat(filepath.Join(tmpDir, "educates"))
+	assert.True(t, os.IsNotExist(err))
+	assert.Empty(t, manager.DevBuilds())
+}
+
+func TestValidateDevBuildName(t *testing.T) {
+	for _, name := range []string{"main", "dev:feature-x", "pr_123"} {
+		assert.NoError(t, ValidateDevBuildName(name), name)
+	}
+	for _, name := range []string{"", "Main", "feature.x", "-x", "dev:"} {
+		assert.Error(t, ValidateDevBuildName(name), name)
+	}
+}
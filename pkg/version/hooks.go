@@ -0,0 +1,72 @@
+package version
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+)
+
+// Hook names, as used in the hooks section of the config file
+const (
+	HookPreInstall  = "preInstall"
+	HookPostInstall = "postInstall"
+	HookPreUse      = "preUse"
+	HookPostUse     = "postUse"
+)
+
+// hookContext describes the operation a hook runs for
+type hookContext struct {
+	version  string
+	binary   string
+	previous string
+}
+
+// SetHooksEnabled enables or disables running the configured hooks
+func (m *Manager) SetHooksEnabled(enabled bool) {
+	m.noHooks = !enabled
+}
+
+// runHooks runs the commands configured for a hook through the system shell. The hook
+// is described to the commands by EDUCATESENV_* environment variables. The first failing
+// command stops the remaining ones and its error is returned.
+func (m *Manager) runHooks(hook string, commands []string, hc hookContext) error {
+	if m.noHooks || len(commands) == 0 {
+		return nil
+	}
+
+	env := append(os.Environ(),
+		"EDUCATESENV_HOOK="+hook,
+		"EDUCATESENV_VERSION="+hc.version,
+		"EDUCATESENV_BINARY="+hc.binary,
+		"EDUCATESENV_PREVIOUS_VERSION="+hc.previous,
+		"EDUCATESENV_BIN_DIR="+m.config.Local.Dir,
+		"EDUCATESENV_LINK="+m.linkPath(),
+	)
+	for _, command := range commands {
+		cmd := shellCommand(command)
+		cmd.Env = env
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("%s hook %q failed: %w", hook, command, err)
+		}
+	}
+	return nil
+}
+
+// runPostHooks runs hooks after an operation has completed, when failing can no longer
+// undo it, so failures are reported as warnings
+func (m *Manager) runPostHooks(hook string, commands []string, hc hookContext) {
+	if err := m.runHooks(hook, commands, hc); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+}
+
+// shellCommand returns a command that runs command through the system shell
+func shellCommand(command string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.Command("cmd", "/C", command)
+	}
+	return exec.Command("sh", "-c", command)
+}
@@ -0,0 +1,56 @@
+package version
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUseVersionHooks(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hook commands in this test use sh")
+	}
+	manager, tmpDir, cleanup := setupTestManager(t)
+	defer cleanup()
+
+	for _, version := range []string{"v1.0.0", "v2.0.0"} {
+		err := os.WriteFile(filepath.Join(tmpDir, "educates-"+version), []byte("test binary"), 0755)
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, manager.UseVersion("v1.0.0"))
+
+	// Post hooks see the version, binary and previous version
+	logFile := filepath.Join(tmpDir, "hooks.log")
+	manager.config.Hooks.PostUse = []string{`echo "$EDUCATESENV_HOOK $EDUCATESENV_VERSION $EDUCATESENV_PREVIOUS_VERSION $(basename "$EDUCATESENV_BINARY")" >> ` + logFile}
+	assert.NoError(t, manager.UseVersion("v2.0.0"))
+
+	data, err := os.ReadFile(logFile)
+	assert.NoError(t, err)
+	assert.Equal(t, "postUse v2.0.0 v1.0.0 educates-v2.0.0\n", string(data))
+
+	// A failing pre hook aborts the switch
+	manager.config.Hooks.PreUse = []string{"exit 3"}
+	err = manager.UseVersion("v1.0.0")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "preUse hook")
+	active, err := manager.ActiveVersion()
+	assert.NoError(t, err)
+	assert.Equal(t, "v2.0.0", active)
+
+	// Hooks can be disabled
+	manager.SetHooksEnabled(false)
+	assert.NoError(t, manager.UseVersion("v1.0.0"))
+	data, err = os.ReadFile(logFile)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, strings.Count(string(data), "\n"))
+
+	// A failing post hook does not undo the switch
+	manager.SetHooksEnabled(true)
+	manager.config.Hooks.PreUse = nil
+	manager.config.Hooks.PostUse = []string{"exit 1"}
+	assert.NoError(t, manager.UseVersion("v2.0.0"))
+}
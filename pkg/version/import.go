@@ -0,0 +1,162 @@
+package version
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/educates/educatesenv/pkg/semver"
+)
+
+// Ways a local file is imported, as recorded in InstallRecord.Imported
+const (
+	// ImportCopy means the file was copied into the bin directory
+	ImportCopy = "copy"
+	// ImportLink means the bin directory holds a symlink to the file
+	ImportLink = "link"
+)
+
+// ImportOptions describes a local binary to import as an installed version
+type ImportOptions struct {
+	// Source is the binary to import
+	Source string
+	// Version is the version to install the binary as
+	Version string
+	// Link symlinks to the binary instead of copying it
+	Link bool
+	// Overwrite replaces an installed version of the same name
+	Overwrite bool
+	// Check runs the binary's version command and requires it to report Version
+	Check bool
+	// Activate sets the version as active after importing it
+	Activate bool
+}
+
+// ImportVersion installs a local binary, such as one built by CI or copied into an
+// air-gapped lab, as if it had been downloaded. Its checksum and origin are recorded in
+// the install registry.
+func (m *Manager) ImportVersion(opts ImportOptions) error {
+	version := opts.Version
+	if IsDevVersion(version) {
+		return fmt.Errorf("%s is reserved for development builds; use `educatesenv dev add` instead", version)
+	}
+	if _, err := semver.Parse(version); err != nil || strings.ContainsAny(version, `/\`) {
+		return fmt.Errorf("invalid version %q: import a binary as a version such as 3.2.1", version)
+	}
+
+	source, err := filepath.Abs(opts.Source)
+	if err != nil {
+		return fmt.Errorf("failed to resolve %s: %w", opts.Source, err)
+	}
+	fi, err := m.fs.Stat(source)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", opts.Source, err)
+	}
+	if !fi.Mode().IsRegular() {
+		return fmt.Errorf("%s is not a regular file", opts.Source)
+	}
+	if opts.Check {
+		if err := checkReportedVersion(source, version); err != nil {
+			return err
+		}
+	}
+
+	unlock, err := m.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	binDir := m.config.Local.Dir
+	if err := m.fs.MkdirAll(binDir, 0o755); err != nil {
+		return fmt.Errorf("failed to create bin directory %s: %w", binDir, err)
+	}
+	binaryPath := m.binaryPath(version)
+	_, err = m.fs.Stat(binaryPath)
+	versionExists := err == nil
+	if versionExists && !opts.Overwrite {
+		return fmt.Errorf("version %s is already installed; use --overwrite to replace it", version)
+	}
+
+	previous, err := m.ActiveVersion()
+	if err != nil {
+		return err
+	}
+	hc := hookContext{version: version, binary: binaryPath, previous: previous}
+	if err := m.runHooks(HookPreInstall, m.config.Hooks.PreInstall, hc); err != nil {
+		return fmt.Errorf("aborting import of %s: %w", version, err)
+	}
+
+	digest, size, err := m.hashFile(source)
+	if err != nil {
+		return fmt.Errorf("failed to hash %s: %w", source, err)
+	}
+
+	// Stage next to the final location and rename it into place, as the version may be active
+	tmpPath := filepath.Join(binDir, "."+filepath.Base(binaryPath)+".import")
+	_ = m.fs.Remove(tmpPath)
+	imported := ImportCopy
+	if opts.Link {
+		imported = ImportLink
+		err = m.symlink(m.linkTarget(source, binDir), tmpPath)
+	} else {
+		err = m.copyFile(source, tmpPath)
+		if err == nil {
+			err = m.fs.Chmod(tmpPath, 0o755)
+		}
+	}
+	if err != nil {
+		_ = m.fs.Remove(tmpPath)
+		return fmt.Errorf("failed to %s %s into %s: %w", imported, source, binDir, err)
+	}
+	if err := m.fs.Rename(tmpPath, binaryPath); err != nil {
+		_ = m.fs.Remove(tmpPath)
+		return fmt.Errorf("failed to move imported binary to %s: %w", binaryPath, err)
+	}
+
+	if err := m.recordInstall(&InstallRecord{
+		Version:            version,
+		Source:             source,
+		Asset:              filepath.Base(source),
+		SHA256:             digest,
+		Size:               size,
+		InstalledAt:        time.Now().UTC(),
+		Overwrite:          versionExists,
+		EducatesenvVersion: Version,
+		Imported:           imported,
+	}); err != nil {
+		return fmt.Errorf("imported %s but failed to record it: %w", version, err)
+	}
+	fmt.Printf("educates %s imported successfully from %s.\n", version, source)
+	m.runPostHooks(HookPostInstall, m.config.Hooks.PostInstall, hc)
+
+	if opts.Activate {
+		if err := m.useVersion(version); err != nil {
+			return fmt.Errorf("import succeeded but failed to set version %s as active: %w", version, err)
+		}
+		fmt.Printf("educates %s is now active.\n", version)
+	}
+	return nil
+}
+
+// checkReportedVersion runs `binary version` and checks that its output names version
+func checkReportedVersion(binary, version string) error {
+	cmd := exec.Command(binary, "version")
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to run `%s version`: %w", binary, err)
+	}
+
+	want := strings.TrimPrefix(version, "v")
+	for _, field := range strings.Fields(out.String()) {
+		if strings.TrimPrefix(field, "v") == want {
+			return nil
+		}
+	}
+	return fmt.Errorf("%s reports %q, not version %s", binary, strings.TrimSpace(out.String()), version)
+}
@@ -0,0 +1,73 @@
+package version
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImportVersion(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the imported binary in this test is a shell script")
+	}
+	manager, tmpDir, cleanup := setupTestManager(t)
+	defer cleanup()
+
+	source := filepath.Join(t.TempDir(), "educates")
+	err := os.WriteFile(source, []byte("#!/bin/sh\necho 'educates version v3.3.0-ci.7'\n"), 0o755)
+	assert.NoError(t, err)
+
+	// The version check must find the version in the output
+	err = manager.ImportVersion(ImportOptions{Source: source, Version: "3.3.0", Check: true})
+	assert.ErrorContains(t, err, "not version 3.3.0")
+
+	err = manager.ImportVersion(ImportOptions{Source: source, Version: "3.3.0-ci.7", Check: true, Activate: true})
+	assert.NoError(t, err)
+	active, err := manager.ActiveVersion()
+	assert.NoError(t, err)
+	assert.Equal(t, "3.3.0-ci.7", active)
+
+	records, err := manager.InstallRecords()
+	assert.NoError(t, err)
+	record := records["3.3.0-ci.7"]
+	assert.Equal(t, source, record.Source)
+	assert.Equal(t, ImportCopy, record.Imported)
+	assert.Len(t, record.SHA256, 64)
+
+	results, err := manager.Verify("3.3.0-ci.7")
+	assert.NoError(t, err)
+	assert.Equal(t, VerifyOK, results[0].Status)
+
+	// Importing over an installed version needs Overwrite
+	err = manager.ImportVersion(ImportOptions{Source: source, Version: "3.3.0-ci.7"})
+	assert.ErrorContains(t, err, "already installed")
+
+	// A linked import follows changes to the source, which verify reports
+	err = manager.ImportVersion(ImportOptions{Source: source, Version: "3.3.0-ci.7", Link: true, Overwrite: true})
+	assert.NoError(t, err)
+	target, err := os.Readlink(filepath.Join(tmpDir, "educates-3.3.0-ci.7"))
+	assert.NoError(t, err)
+	assert.Equal(t, source, target)
+
+	assert.NoError(t, os.WriteFile(source, []byte("#!/bin/sh\necho changed\n"), 0o755))
+	results, err = manager.Verify("3.3.0-ci.7")
+	assert.NoError(t, err)
+	assert.Equal(t, VerifyModified, results[0].Status)
+}
+
+func TestImportVersionInvalid(t *testing.T) {
+	manager, tmpDir, cleanup := setupTestManager(t)
+	defer cleanup()
+
+	source := filepath.Join(tmpDir, "source")
+	assert.NoError(t, os.WriteFile(source, []byte("binary"), 0o755))
+
+	for _, version := range []string{"develop", "dev:main", "latest", "../3.2.1"} {
+		assert.Error(t, manager.ImportVersion(ImportOptions{Source: source, Version: version}), version)
+	}
+	assert.Error(t, manager.ImportVersion(ImportOptions{Source: tmpDir, Version: "3.2.1"}))
+	assert.Error(t, manager.ImportVersion(ImportOptions{Source: filepath.Join(tmpDir, "missing"), Version: "3.2.1"}))
+}
@@ -1,31 +1,66 @@
 package version
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"io"
-	"net/http"
 	"os"
 	"path/filepath"
 	"runtime"
 	"strings"
+	"time"
+
+	"github.com/spf13/afero"
 
 	"github.com/educates/educatesenv/pkg/config"
 	"github.com/educates/educatesenv/pkg/github"
+	"github.com/educates/educatesenv/pkg/lock"
+	"github.com/educates/educatesenv/pkg/lockfile"
+	"github.com/educates/educatesenv/pkg/paths"
 	"github.com/educates/educatesenv/pkg/platform"
 )
 
+// defaultLockTimeout is how long a mutating operation waits for another educatesenv process
+const defaultLockTimeout = 2 * time.Minute
+
 // Manager handles version-related operations
 type Manager struct {
-	config *config.Config
-	github *github.Client
+	config      *config.Config
+	github      *github.Client
+	fs          afero.Fs
+	goos        string
+	goarch      string
+	lockPath    string
+	lockTimeout time.Duration
+	aliasesPath string
+	devDir      string
+	noHooks     bool
+	// portableRoot is the tree everything lives in in portable mode, which may be moved, so
+	// links within it must be relative. It is empty outside portable mode.
+	portableRoot string
+	// lockfile pins the binary installed for one version, if set
+	lockfile *lockfile.Lockfile
 }
 
 // New creates a new version manager
 func New(cfg *config.Config, gh *github.Client) *Manager {
-	return &Manager{
-		config: cfg,
-		github: gh,
+	layout := paths.Resolve()
+	m := &Manager{
+		config:      cfg,
+		github:      gh,
+		fs:          osFs{},
+		goos:        runtime.GOOS,
+		goarch:      runtime.GOARCH,
+		lockPath:    layout.LockFile(),
+		lockTimeout: defaultLockTimeout,
+		aliasesPath: layout.AliasesFile(),
+		devDir:      layout.DevBuildsDir(),
 	}
+	if layout.Portable {
+		m.portableRoot = layout.Config
+	}
+	return m
 }
 
 // ValidateDevelopmentMode checks and cleans up development symlinks when development mode is disabled
@@ -34,14 +69,19 @@ func (m *Manager) ValidateDevelopmentMode() error {
 		return nil
 	}
 
-	symlinkPath := filepath.Join(m.config.Local.Dir, "educates")
-	isDev, err := m.isDevSymlink(symlinkPath)
+	isDev, err := m.isDevActive()
 	if err != nil {
 		return fmt.Errorf("failed to validate development mode: %w", err)
 	}
 
 	if isDev {
-		if err := os.Remove(symlinkPath); err != nil {
+		unlock, err := m.lock()
+		if err != nil {
+			return err
+		}
+		defer unlock()
+
+		if err := m.deactivate(); err != nil {
 			return fmt.Errorf("failed to remove development symlink: %w", err)
 		}
 		return fmt.Errorf("development mode is disabled; removed symlink to development binary. Please use 'educatesenv use <version>' to select a version")
@@ -50,22 +90,14 @@ func (m *Manager) ValidateDevelopmentMode() error {
 	return nil
 }
 
-// isDevSymlink checks if the symlink points to a development binary
-func (m *Manager) isDevSymlink(symlinkPath string) (bool, error) {
-	target, err := os.Readlink(symlinkPath)
-	if err != nil {
-		if os.IsNotExist(err) {
-			return false, nil
-		}
-		return false, fmt.Errorf("failed to read symlink: %w", err)
-	}
-
-	// Resolve relative symlink if needed
-	if !filepath.IsAbs(target) {
-		target = filepath.Join(filepath.Dir(symlinkPath), target)
+// isDevActive checks if the active link points to a development binary
+func (m *Manager) isDevActive() (bool, error) {
+	target, err := m.activeTarget()
+	if err != nil || target == "" {
+		return false, err
 	}
 
-	// A symlink is considered a development symlink if:
+	// A link is considered a development link if:
 	// 1. Development mode is disabled AND
 	// 2. The target is outside the managed bin directory OR doesn't have the expected binary prefix
 	return !strings.HasPrefix(target, m.config.Local.Dir) || !strings.HasPrefix(filepath.Base(target), platform.BinaryPrefix), nil
@@ -73,32 +105,94 @@ func (m *Manager) isDevSymlink(symlinkPath string) (bool, error) {
 
 // UseVersion sets a version as active
 func (m *Manager) UseVersion(version string) error {
-	symlinkPath := filepath.Join(m.config.Local.Dir, "educates")
+	unlock, err := m.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
 
-	// Handle development version
-	if version == "develop" {
-		if !m.config.Development.Enabled {
-			return fmt.Errorf("development mode is not enabled. Enable it in the config file by setting development.enabled to true")
-		}
-		if m.config.Development.BinaryLocation == "" {
-			return fmt.Errorf("development binary location is not set. Set development.binaryLocation in the config file")
-		}
-		return m.createSymlink(m.config.Development.BinaryLocation, symlinkPath)
+	return m.useVersion(version)
+}
+
+// useVersion sets a version as active. The caller must hold the lock.
+func (m *Manager) useVersion(version string) error {
+	binary, err := m.Binary(version)
+	if err != nil {
+		return err
 	}
 
-	// Handle regular version
-	binaryPath := filepath.Join(m.config.Local.Dir, fmt.Sprintf("%s%s", platform.BinaryPrefix, version))
-	err := m.createSymlink(binaryPath, symlinkPath)
+	previous, err := m.ActiveVersion()
 	if err != nil {
-		return fmt.Errorf("%w", err)
+		return err
+	}
+	hc := hookContext{version: version, binary: binary, previous: previous}
+	if err := m.runHooks(HookPreUse, m.config.Hooks.PreUse, hc); err != nil {
+		return fmt.Errorf("aborting switch to %s: %w", version, err)
+	}
+
+	if err := m.activate(binary, m.linkPath()); err != nil {
+		return err
 	}
 
+	// Last-used times drive `educatesenv prune --unused-since`, but are not worth failing the switch over
+	if !IsDevVersion(version) {
+		if err := m.recordUse(version); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to record use of version %s: %v\n", version, err)
+		}
+	}
+
+	m.runPostHooks(HookPostUse, m.config.Hooks.PostUse, hc)
 	return nil
 }
 
+// IsInstalled reports whether a version is installed in the bin directory
+func (m *Manager) IsInstalled(version string) bool {
+	fi, err := m.fs.Stat(m.binaryPath(version))
+	return err == nil && !fi.IsDir()
+}
+
+// InstalledVersions returns the versions installed in the bin directory. Nothing is
+// installed if the bin directory does not exist yet.
+func (m *Manager) InstalledVersions() ([]string, error) {
+	files, err := afero.ReadDir(m.fs, m.config.Local.Dir)
+	if os.IsNotExist(err) {
+		return []string{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read bin directory %s: %w", m.config.Local.Dir, err)
+	}
+
+	var versions []string
+	for _, file := range files {
+		if file.IsDir() {
+			continue
+		}
+		if version, ok := platform.ParseVersionBinaryName(file.Name(), m.goos); ok {
+			versions = append(versions, version)
+		}
+	}
+	return versions, nil
+}
+
+// ActiveVersion returns the currently active version, "develop" or dev:<name> if a
+// development build is active, or an empty string if no version is active
+func (m *Manager) ActiveVersion() (string, error) {
+	target, err := m.activeTarget()
+	if err != nil || target == "" {
+		return "", err
+	}
+
+	if version, ok := m.activeDevBuild(target); ok {
+		return version, nil
+	}
+
+	version, _ := platform.ParseVersionBinaryName(filepath.Base(target), m.goos)
+	return version, nil
+}
+
 // GetPlatformBinaryName returns the platform-specific binary name
 func (m *Manager) GetPlatformBinaryName() (string, error) {
-	os, arch := runtime.GOOS, runtime.GOARCH
+	os, arch := m.goos, m.goarch
 
 	if !platform.IsSupportedPlatform(os, arch) {
 		return "", fmt.Errorf("unsupported platform: %s-%s", os, arch)
@@ -109,18 +203,34 @@ func (m *Manager) GetPlatformBinaryName() (string, error) {
 
 // InstallVersion installs a specific version of educates
 func (m *Manager) InstallVersion(version string, force bool, activate bool) error {
+	unlock, err := m.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if err := m.installVersion(version, force, activate); err != nil {
+		return err
+	}
+	m.warnAutoPrune(version)
+	return nil
+}
+
+// installVersion installs a specific version of educates. The caller must hold the lock.
+func (m *Manager) installVersion(version string, force bool, activate bool) error {
 	binDir := m.config.Local.Dir
-	if err := os.MkdirAll(binDir, 0o755); err != nil {
+	if err := m.fs.MkdirAll(binDir, 0o755); err != nil {
 		return fmt.Errorf("failed to create bin directory %s: %w", binDir, err)
 	}
 
 	// Check if version already exists
-	binaryPath := filepath.Join(binDir, fmt.Sprintf("%s%s", platform.BinaryPrefix, version))
-	_, err := os.Stat(binaryPath)
-	versionExists := err == nil
+	versionExists := m.IsInstalled(version)
 
 	// Handle installation
 	if versionExists && !force {
+		if err := m.checkPinned(version); err != nil {
+			return err
+		}
 		fmt.Printf("Version %s is already installed.\n", version)
 	} else {
 		if versionExists {
@@ -129,29 +239,27 @@ func (m *Manager) InstallVersion(version string, force bool, activate bool) erro
 			fmt.Printf("Installing version %s...\n", version)
 		}
 
-		assetName, err := m.GetPlatformBinaryName()
+		report := func(message string) { fmt.Println(message) }
+		dl, err := m.prepareDownload(version, versionExists, nil)
 		if err != nil {
-			return fmt.Errorf("failed to determine platform binary name: %w", err)
+			return err
 		}
-
-		downloadURL, err := m.github.GetReleaseAssetURL(version, assetName)
-		if err != nil {
-			return err // Pass through the user-friendly error from GitHub client
+		if err := m.runHooks(HookPreInstall, m.config.Hooks.PreInstall, dl.hooks); err != nil {
+			return fmt.Errorf("aborting install of %s: %w", version, err)
 		}
-
-		fmt.Printf("Downloading %s...\n", downloadURL)
-		if err := m.downloadFile(downloadURL, binaryPath); err != nil {
-			return fmt.Errorf("failed to download binary (check your internet connection and try again): %w", err)
+		if err := m.download(dl, report); err != nil {
+			return err
 		}
-		if err := os.Chmod(binaryPath, 0o755); err != nil {
-			return fmt.Errorf("failed to set executable permissions on %s: %w", binaryPath, err)
+		if err := m.completeDownload(dl); err != nil {
+			return err
 		}
 		fmt.Printf("educates %s installed successfully.\n", version)
+		m.runPostHooks(HookPostInstall, m.config.Hooks.PostInstall, dl.hooks)
 	}
 
 	// Handle activation if requested
 	if activate {
-		if err := m.UseVersion(version); err != nil {
+		if err := m.useVersion(version); err != nil {
 			return fmt.Errorf("installation succeeded but failed to set version %s as active: %w", version, err)
 		}
 		fmt.Printf("educates %s is now active.\n", version)
@@ -160,60 +268,171 @@ func (m *Manager) InstallVersion(version string, force bool, activate bool) erro
 	return nil
 }
 
-// createSymlink creates a symlink from source to target
-func (m *Manager) createSymlink(source, target string) error {
-	// Check if source exists
-	if _, err := os.Stat(source); err != nil {
-		if os.IsNotExist(err) {
-			return fmt.Errorf("binary not found at %s", source)
+// pendingDownload describes a version being downloaded into the bin directory
+type pendingDownload struct {
+	version      string
+	assetName    string
+	url          string
+	binaryPath   string
+	downloadPath string
+	overwrite    bool
+	// assets are the download URLs of the assets of the release, keyed by name
+	assets map[string]string
+	// expectedSHA256 is the digest the download must have, if not empty
+	expectedSHA256 string
+	hooks          hookContext
+	record         *InstallRecord
+}
+
+// prepareDownload looks up the release asset of a version for this platform in assets, the
+// assets of the release, which are fetched if nil
+func (m *Manager) prepareDownload(version string, overwrite bool, assets map[string]string) (*pendingDownload, error) {
+	assetName, err := m.GetPlatformBinaryName()
+	if err != nil {
+		return nil, fmt.Errorf("failed to determine platform binary name: %w", err)
+	}
+
+	if assets == nil {
+		if assets, err = m.github.GetReleaseAssets(version); err != nil {
+			return nil, err // Pass through the user-friendly error from GitHub client
 		}
-		return fmt.Errorf("failed to check binary: %w", err)
+	}
+	downloadURL, ok := assets[assetName]
+	if !ok {
+		return nil, fmt.Errorf("binary for %s is not available for your platform (%s). Please check supported platforms in the documentation", version, assetName)
 	}
 
-	// Remove existing symlink if it exists
-	if fi, err := os.Lstat(target); err == nil {
-		if fi.Mode()&os.ModeSymlink != 0 {
-			if err := os.Remove(target); err != nil {
-				return fmt.Errorf("failed to remove existing symlink: %w", err)
-			}
-		} else {
-			return fmt.Errorf("%s exists and is not a symlink", target)
+	var expectedSHA256 string
+	if pin, ok := m.pinned(version); ok {
+		if pin.Name != assetName {
+			return nil, fmt.Errorf("the lockfile pins asset %s but this platform uses %s", pin.Name, assetName)
 		}
-	} else if !os.IsNotExist(err) {
-		return fmt.Errorf("failed to check symlink: %w", err)
+		expectedSHA256 = pin.SHA256
+	}
+
+	previous, err := m.ActiveVersion()
+	if err != nil {
+		return nil, err
 	}
+	binaryPath := m.binaryPath(version)
+	return &pendingDownload{
+		version:    version,
+		assetName:  assetName,
+		url:        downloadURL,
+		assets:     assets,
+		binaryPath: binaryPath,
+		// Download next to the final location and rename it into place, so that a failed or
+		// interrupted download never leaves a truncated binary behind
+		downloadPath:   filepath.Join(m.config.Local.Dir, "."+filepath.Base(binaryPath)+".download"),
+		overwrite:      overwrite,
+		expectedSHA256: expectedSHA256,
+		hooks:          hookContext{version: version, binary: binaryPath, previous: previous},
+	}, nil
+}
 
-	// Create new symlink
-	relTarget, err := filepath.Rel(filepath.Dir(target), source)
+// download downloads and verifies a version to its download path, reporting progress to
+// report. It only touches files of that version, so several versions can be downloaded
+// at once.
+func (m *Manager) download(dl *pendingDownload, report func(string)) error {
+	report(fmt.Sprintf("Downloading %s...", dl.url))
+	digest, size, err := m.downloadFile(dl.url, dl.downloadPath)
 	if err != nil {
-		relTarget = source // fallback to absolute path
+		_ = m.fs.Remove(dl.downloadPath)
+		return fmt.Errorf("failed to download binary (check your internet connection and try again): %w", err)
 	}
-	if err := os.Symlink(relTarget, target); err != nil {
-		return fmt.Errorf("failed to create symlink: %w", err)
+	if dl.expectedSHA256 != "" && !strings.EqualFold(digest, dl.expectedSHA256) {
+		_ = m.fs.Remove(dl.downloadPath)
+		return fmt.Errorf("refusing to install %s: %s has SHA-256 %s but the lockfile pins %s", dl.version, dl.assetName, digest, dl.expectedSHA256)
 	}
+	if err := m.fs.Chmod(dl.downloadPath, 0o755); err != nil {
+		_ = m.fs.Remove(dl.downloadPath)
+		return fmt.Errorf("failed to set executable permissions on %s: %w", dl.downloadPath, err)
+	}
+	signature, err := m.verifySignature(dl, digest, report)
+	if err != nil {
+		_ = m.fs.Remove(dl.downloadPath)
+		return fmt.Errorf("refusing to install %s: %w", dl.version, err)
+	}
+	if signature != "" {
+		report(fmt.Sprintf("Verified signature %s.", signature))
+	}
+	dl.record = &InstallRecord{
+		Version:            dl.version,
+		Source:             dl.url,
+		Asset:              dl.assetName,
+		SHA256:             digest,
+		Size:               size,
+		Signature:          signature,
+		InstalledAt:        time.Now().UTC(),
+		Overwrite:          dl.overwrite,
+		EducatesenvVersion: Version,
+	}
+	return nil
+}
 
+// completeDownload moves a downloaded version into place and records its installation.
+// The caller must hold the lock.
+func (m *Manager) completeDownload(dl *pendingDownload) error {
+	if err := m.fs.Rename(dl.downloadPath, dl.binaryPath); err != nil {
+		_ = m.fs.Remove(dl.downloadPath)
+		return fmt.Errorf("failed to move downloaded binary to %s: %w", dl.binaryPath, err)
+	}
+	if err := m.recordInstall(dl.record); err != nil {
+		return fmt.Errorf("installed %s but failed to record it: %w", dl.version, err)
+	}
 	return nil
 }
 
-// downloadFile downloads a file from a URL to a local path
-func (m *Manager) downloadFile(url, outPath string) error {
-	resp, err := http.Get(url)
+// binaryPath returns the path a version is installed at
+func (m *Manager) binaryPath(version string) string {
+	return filepath.Join(m.config.Local.Dir, platform.GetVersionBinaryName(version, m.goos))
+}
+
+// linkPath returns the path of the link to the active version
+func (m *Manager) linkPath() string {
+	return filepath.Join(m.config.Local.Dir, platform.GetActiveBinaryName(m.goos))
+}
+
+// lock takes the educatesenv lock, returning a function that releases it. Operations that
+// change the bin directory hold it so that concurrent processes cannot interleave.
+func (m *Manager) lock() (func(), error) {
+	if m.lockPath == "" {
+		return func() {}, nil
+	}
+
+	l, err := lock.Acquire(m.lockPath, m.lockTimeout, func(pid int) {
+		if pid > 0 {
+			fmt.Fprintf(os.Stderr, "Waiting for another educatesenv (pid %d) to finish...\n", pid)
+		} else {
+			fmt.Fprintln(os.Stderr, "Waiting for another educatesenv to finish...")
+		}
+	})
 	if err != nil {
-		return err
+		return nil, err
+	}
+	return func() {
+		if err := l.Release(); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+	}, nil
+}
+
+// downloadFile downloads the release asset at url to a local path, returning the hex
+// encoded SHA-256 digest and size of the downloaded content
+func (m *Manager) downloadFile(url, outPath string) (digest string, size int64, err error) {
+	body, err := m.github.OpenAsset(url)
+	if err != nil {
+		return "", 0, err
 	}
 	defer func() {
-		if cerr := resp.Body.Close(); cerr != nil && err == nil {
+		if cerr := body.Close(); cerr != nil && err == nil {
 			err = fmt.Errorf("error closing response body: %w", cerr)
 		}
 	}()
 
-	if resp.StatusCode != 200 {
-		return fmt.Errorf("failed to download file: %s", resp.Status)
-	}
-
-	out, err := os.Create(outPath)
+	out, err := m.fs.Create(outPath)
 	if err != nil {
-		return err
+		return "", 0, err
 	}
 	defer func() {
 		if cerr := out.Close(); cerr != nil && err == nil {
@@ -221,6 +440,17 @@ func (m *Manager) downloadFile(url, outPath string) error {
 		}
 	}()
 
-	_, err = io.Copy(out, resp.Body)
-	return err
+	h := sha256.New()
+	size, err = io.Copy(io.MultiWriter(out, h), body)
+	return hex.EncodeToString(h.Sum(nil)), size, err
+}
+
+// osFs is the filesystem used outside of tests. It extends afero's OsFs with hard links.
+type osFs struct {
+	afero.OsFs
+}
+
+// Link creates newname as a hard link to oldname
+func (osFs) Link(oldname, newname string) error {
+	return os.Link(oldname, newname)
 }
@@ -3,10 +3,14 @@ package version
 import (
 	"os"
 	"path/filepath"
+	"sync"
 	"testing"
 
 	"github.com/educates/educatesenv/pkg/config"
 	"github.com/educates/educatesenv/pkg/github"
+	"github.com/educates/educatesenv/pkg/manifest"
+	"github.com/educates/educatesenv/pkg/platform"
+	"github.com/spf13/afero"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -36,6 +40,9 @@ func setupTestManager(t *testing.T) (*Manager, string, func()) {
 
 	// Create manager
 	manager := New(cfg, gh)
+	manager.lockPath = filepath.Join(tmpDir, ".educatesenv.lock")
+	manager.aliasesPath = filepath.Join(tmpDir, "aliases.yaml")
+	manager.devDir = filepath.Join(tmpDir, "dev")
 
 	cleanup := func() {
 		err := os.RemoveAll(tmpDir)
@@ -104,7 +111,208 @@ func TestUseVersion(t *testing.T) {
 	assert.Contains(t, err.Error(), "development mode is not enabled")
 }
 
+func TestUseVersionConcurrent(t *testing.T) {
+	manager, tmpDir, cleanup := setupTestManager(t)
+	defer cleanup()
+
+	versions := []string{"v1.0.0", "v2.0.0"}
+	for _, version := range versions {
+		err := os.WriteFile(filepath.Join(tmpDir, "educates-"+version), []byte("test binary"), 0755)
+		assert.NoError(t, err)
+	}
+
+	// Concurrent switches are serialized by the lock and never leave the link missing
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(version string) {
+			defer wg.Done()
+			assert.NoError(t, manager.UseVersion(version))
+			_, err := os.Stat(filepath.Join(tmpDir, "educates"))
+			assert.NoError(t, err)
+		}(versions[i%2])
+	}
+	wg.Wait()
+
+	// No temporary links are left behind
+	entries, err := os.ReadDir(tmpDir)
+	assert.NoError(t, err)
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	assert.ElementsMatch(t, []string{".educatesenv.lock", "educates", "educates-v1.0.0", "educates-v2.0.0", "installed.json"}, names)
+}
+
+// linkingFs is an in-memory filesystem that supports hard links but not symlinks,
+// like Windows without Developer Mode
+type linkingFs struct {
+	afero.Fs
+	links int
+}
+
+func (l *linkingFs) Link(oldname, newname string) error {
+	l.links++
+	data, err := afero.ReadFile(l.Fs, oldname)
+	if err != nil {
+		return err
+	}
+	return afero.WriteFile(l.Fs, newname, data, 0o755)
+}
+
+func setupWindowsManager(t *testing.T, fs afero.Fs) (*Manager, string) {
+	binDir := filepath.Join(string(filepath.Separator), "educatesenv", "bin")
+	assert.NoError(t, fs.MkdirAll(binDir, 0o755))
+
+	cfg := &config.Config{
+		Github: config.GithubConfig{
+			Org:        "testorg",
+			Repository: "testrepo",
+		},
+		Local: config.LocalConfig{
+			Dir: binDir,
+		},
+	}
+
+	manager := New(cfg, github.New(cfg))
+	manager.fs = fs
+	manager.lockPath = filepath.Join(t.TempDir(), "educatesenv.lock")
+	manager.goos = platform.Windows
+	manager.goarch = platform.AMD64
+	return manager, binDir
+}
+
+func TestUseVersionWindowsCopy(t *testing.T) {
+	manager, binDir := setupWindowsManager(t, afero.NewMemMapFs())
+
+	for _, version := range []string{"v1.0.0", "v2.0.0"} {
+		err := afero.WriteFile(manager.fs, filepath.Join(binDir, "educates-"+version+".exe"), []byte("binary "+version), 0o755)
+		assert.NoError(t, err)
+	}
+
+	// Without symlink support the binary is copied
+	err := manager.UseVersion("v1.0.0")
+	assert.NoError(t, err)
+	data, err := afero.ReadFile(manager.fs, filepath.Join(binDir, "educates.exe"))
+	assert.NoError(t, err)
+	assert.Equal(t, "binary v1.0.0", string(data))
+
+	active, err := manager.ActiveVersion()
+	assert.NoError(t, err)
+	assert.Equal(t, "v1.0.0", active)
+
+	// Switching replaces the previous copy
+	err = manager.UseVersion("v2.0.0")
+	assert.NoError(t, err)
+	data, err = afero.ReadFile(manager.fs, filepath.Join(binDir, "educates.exe"))
+	assert.NoError(t, err)
+	assert.Equal(t, "binary v2.0.0", string(data))
+
+	active, err = manager.ActiveVersion()
+	assert.NoError(t, err)
+	assert.Equal(t, "v2.0.0", active)
+
+	// Neither the active copy nor the marker are listed as versions
+	versions, err := manager.InstalledVersions()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"v1.0.0", "v2.0.0"}, versions)
+
+	// Versions are looked up with the .exe suffix
+	err = manager.UseVersion("v3.0.0")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "educates-v3.0.0.exe")
+}
+
+func TestUseVersionWindowsHardLink(t *testing.T) {
+	fs := &linkingFs{Fs: afero.NewMemMapFs()}
+	manager, binDir := setupWindowsManager(t, fs)
+
+	err := afero.WriteFile(fs, filepath.Join(binDir, "educates-v1.0.0.exe"), []byte("binary"), 0o755)
+	assert.NoError(t, err)
+
+	err = manager.UseVersion("v1.0.0")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, fs.links)
+
+	active, err := manager.ActiveVersion()
+	assert.NoError(t, err)
+	assert.Equal(t, "v1.0.0", active)
+}
+
+func TestUseVersionWindowsForeignBinary(t *testing.T) {
+	manager, binDir := setupWindowsManager(t, afero.NewMemMapFs())
+
+	err := afero.WriteFile(manager.fs, filepath.Join(binDir, "educates-v1.0.0.exe"), []byte("binary"), 0o755)
+	assert.NoError(t, err)
+
+	// An educates.exe not created by educatesenv is left alone
+	err = afero.WriteFile(manager.fs, filepath.Join(binDir, "educates.exe"), []byte("foreign"), 0o755)
+	assert.NoError(t, err)
+
+	err = manager.UseVersion("v1.0.0")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "is not a symlink")
+
+	active, err := manager.ActiveVersion()
+	assert.NoError(t, err)
+	assert.Empty(t, active)
+}
+
+func TestValidateDevelopmentModeWindows(t *testing.T) {
+	manager, binDir := setupWindowsManager(t, afero.NewMemMapFs())
+
+	devBinary := filepath.Join(string(filepath.Separator), "src", "educates.exe")
+	err := afero.WriteFile(manager.fs, devBinary, []byte("dev"), 0o755)
+	assert.NoError(t, err)
+
+	manager.config.Development.Enabled = true
+	manager.config.Development.BinaryLocation = devBinary
+	err = manager.UseVersion("develop")
+	assert.NoError(t, err)
+
+	active, err := manager.ActiveVersion()
+	assert.NoError(t, err)
+	assert.Equal(t, "develop", active)
+
+	// Disabling development mode removes the copied development binary
+	manager.config.Development.Enabled = false
+	err = manager.ValidateDevelopmentMode()
+	assert.Error(t, err)
+	_, err = manager.fs.Stat(filepath.Join(binDir, "educates.exe"))
+	assert.True(t, os.IsNotExist(err))
+}
+
 // TODO: Implement proper mocking for GitHub client
 // func TestInstallVersion(t *testing.T) {
 // 	...
 // }
+
+func TestLinkTarget(t *testing.T) {
+	m := &Manager{}
+	assert.Equal(t, "/opt/ee/bin/educates-3.2.1", m.linkTarget("/opt/ee/bin/educates-3.2.1", "/opt/ee/bin"))
+
+	// In portable mode links within the tree are relative, and others stay absolute
+	m.portableRoot = "/opt/ee"
+	assert.Equal(t, "educates-3.2.1", m.linkTarget("/opt/ee/bin/educates-3.2.1", "/opt/ee/bin"))
+	assert.Equal(t, filepath.Join("..", "dev", "main", "educates"), m.linkTarget("/opt/ee/dev/main/educates", "/opt/ee/bin"))
+	assert.Equal(t, "/home/dev/educates", m.linkTarget("/home/dev/educates", "/opt/ee/bin"))
+	assert.Equal(t, "/opt/ee-other/educates", m.linkTarget("/opt/ee-other/educates", "/opt/ee/bin"))
+}
+
+func TestInstalledVersionsFreshRoot(t *testing.T) {
+	manager, tmpDir, cleanup := setupTestManager(t)
+	defer cleanup()
+	manager.config.Local.Dir = filepath.Join(tmpDir, "missing", "bin")
+
+	versions, err := manager.InstalledVersions()
+	assert.NoError(t, err)
+	assert.Empty(t, versions)
+	active, err := manager.ActiveVersion()
+	assert.NoError(t, err)
+
+	// Every version in a manifest is reported as missing on a fresh machine
+	m := &manifest.Manifest{Versions: []string{"3.2.3", "3.1.4"}, Default: "3.3.0"}
+	drift := manifest.Compare(m, manifest.State{Installed: versions, Active: active})
+	assert.Equal(t, []string{"3.1.4", "3.2.3", "3.3.0"}, drift.Missing)
+	assert.Empty(t, drift.Unlisted)
+}
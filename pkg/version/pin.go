@@ -0,0 +1,119 @@
+package version
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/educates/educatesenv/pkg/lockfile"
+	"github.com/educates/educatesenv/pkg/platform"
+)
+
+// LockVersion builds a lockfile pinning a release, recording the binary of every supported
+// platform published in it along with its digest. Each binary is downloaded to compute
+// its digest, up to workers at once.
+func (m *Manager) LockVersion(version string, workers int) (*lockfile.Lockfile, error) {
+	assets, err := m.github.GetReleaseAssets(version)
+	if err != nil {
+		return nil, err
+	}
+
+	type target struct {
+		key, name, url string
+	}
+	var targets []target
+	for _, p := range platform.SupportedPlatforms() {
+		name := platform.GetPlatformBinaryName(p[0], p[1])
+		if url, ok := assets[name]; ok {
+			targets = append(targets, target{key: lockfile.Key(p[0], p[1]), name: name, url: url})
+		}
+	}
+	if len(targets) == 0 {
+		return nil, fmt.Errorf("release %s has no binaries for any supported platform", version)
+	}
+
+	lf := &lockfile.Lockfile{
+		Version: version,
+		Source:  m.source(),
+		Assets:  map[string]lockfile.Asset{},
+	}
+	var mu sync.Mutex
+	var errs []string
+	runPool(len(targets), workers, func(i int) {
+		digest, err := m.hashAsset(targets[i].url)
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", targets[i].name, err))
+			return
+		}
+		lf.Assets[targets[i].key] = lockfile.Asset{Name: targets[i].name, SHA256: digest}
+	})
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("failed to compute digests: %s", strings.Join(errs, "; "))
+	}
+	return lf, nil
+}
+
+// UseLockfile makes installs of the version pinned by a lockfile download the binary it
+// pins for this platform, and refuse it if its digest differs. An installed copy of the
+// version must have the pinned digest too.
+func (m *Manager) UseLockfile(lf *lockfile.Lockfile) error {
+	if lf.Source != "" && lf.Source != m.source() {
+		return fmt.Errorf("the lockfile pins a release from %s, but releases are installed from %s", lf.Source, m.source())
+	}
+	if _, ok := lf.Asset(m.goos, m.goarch); !ok {
+		return fmt.Errorf("the lockfile pins no binary for %s", lockfile.Key(m.goos, m.goarch))
+	}
+	m.lockfile = lf
+	return nil
+}
+
+// pinned returns the binary pinned for a version by the lockfile in use, if any
+func (m *Manager) pinned(version string) (lockfile.Asset, bool) {
+	if m.lockfile == nil || m.lockfile.Version != version {
+		return lockfile.Asset{}, false
+	}
+	return m.lockfile.Asset(m.goos, m.goarch)
+}
+
+// checkPinned checks that the installed binary of a version has the digest pinned by the
+// lockfile in use, if it pins the version
+func (m *Manager) checkPinned(version string) error {
+	pin, ok := m.pinned(version)
+	if !ok {
+		return nil
+	}
+	digest, _, err := m.hashFile(m.binaryPath(version))
+	if err != nil {
+		return fmt.Errorf("failed to hash version %s: %w", version, err)
+	}
+	if !strings.EqualFold(digest, pin.SHA256) {
+		return fmt.Errorf("installed version %s has SHA-256 %s but the lockfile pins %s. Reinstall it with --overwrite", version, digest, pin.SHA256)
+	}
+	return nil
+}
+
+// source returns the repository releases are installed from, as org/repository
+func (m *Manager) source() string {
+	return m.config.Github.Org + "/" + m.config.Github.Repository
+}
+
+// hashAsset downloads the release asset at url and returns the hex encoded SHA-256 digest
+// of its content
+func (m *Manager) hashAsset(url string) (string, error) {
+	body, err := m.github.OpenAsset(url)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = body.Close() }()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, body); err != nil {
+		return "", fmt.Errorf("failed to download %s: %w", url, err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,109 @@
+package version
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/educates/educatesenv/pkg/lockfile"
+	"github.com/educates/educatesenv/pkg/platform"
+)
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func TestHashAsset(t *testing.T) {
+	manager, _, cleanup := setupTestManager(t)
+	defer cleanup()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte("binary"))
+	}))
+	defer server.Close()
+
+	digest, err := manager.hashAsset(server.URL + "/asset")
+	assert.NoError(t, err)
+	assert.Equal(t, sha256Hex([]byte("binary")), digest)
+
+	_, err = manager.hashAsset(server.URL + "/missing")
+	assert.Error(t, err)
+}
+
+func TestUseLockfile(t *testing.T) {
+	manager, _, cleanup := setupTestManager(t)
+	defer cleanup()
+
+	content := []byte("binary")
+	assert.NoError(t, os.WriteFile(manager.binaryPath("3.2.1"), content, 0o755))
+
+	key := lockfile.Key(manager.goos, manager.goarch)
+	lf := &lockfile.Lockfile{
+		Version: "3.2.1",
+		Source:  "testorg/testrepo",
+		Assets: map[string]lockfile.Asset{
+			key: {Name: platform.GetPlatformBinaryName(manager.goos, manager.goarch), SHA256: sha256Hex(content)},
+		},
+	}
+	assert.NoError(t, manager.UseLockfile(lf))
+
+	// An installed copy with the pinned digest is accepted without downloading
+	assert.NoError(t, manager.InstallVersion("3.2.1", false, false))
+
+	// One with another digest is not
+	lf.Assets[key] = lockfile.Asset{Name: lf.Assets[key].Name, SHA256: strings.Repeat("0", 64)}
+	assert.ErrorContains(t, manager.InstallVersion("3.2.1", false, false), "lockfile pins")
+	results, err := manager.InstallVersions([]string{"3.2.1"}, false, 1, func(string, string) {})
+	assert.NoError(t, err)
+	assert.Equal(t, InstallFailed, results[0].Status)
+
+	// The lockfile must be for the configured source and this platform
+	lf.Source = "otherorg/testrepo"
+	assert.ErrorContains(t, manager.UseLockfile(lf), "otherorg/testrepo")
+	lf.Source = "testorg/testrepo"
+	delete(lf.Assets, key)
+	assert.ErrorContains(t, manager.UseLockfile(lf), "no binary for "+key)
+}
+
+func TestDownloadPinnedDigest(t *testing.T) {
+	manager, tmpDir, cleanup := setupTestManager(t)
+	defer cleanup()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("binary"))
+	}))
+	defer server.Close()
+
+	dl := &pendingDownload{
+		version:        "3.2.1",
+		assetName:      platform.GetPlatformBinaryName(manager.goos, manager.goarch),
+		url:            server.URL + "/asset",
+		downloadPath:   filepath.Join(tmpDir, ".educates-3.2.1.download"),
+		expectedSHA256: strings.Repeat("0", 64),
+	}
+
+	// A binary whose digest differs from the pinned one is rejected and removed
+	err := manager.download(dl, func(string) {})
+	assert.ErrorContains(t, err, "refusing to install 3.2.1")
+	assert.ErrorContains(t, err, "the lockfile pins "+strings.Repeat("0", 64))
+	assert.NoFileExists(t, dl.downloadPath)
+	assert.Nil(t, dl.record)
+
+	// One with the pinned digest is accepted
+	dl.expectedSHA256 = strings.ToUpper(sha256Hex([]byte("binary")))
+	assert.NoError(t, manager.download(dl, func(string) {}))
+	assert.FileExists(t, dl.downloadPath)
+	assert.Equal(t, sha256Hex([]byte("binary")), dl.record.SHA256)
+}
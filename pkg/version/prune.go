@@ -0,0 +1,210 @@
+package version
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/educates/educatesenv/pkg/config"
+	"github.com/educates/educatesenv/pkg/semver"
+)
+
+// PrunePolicy selects installed versions to remove. Zero fields are not applied, and a
+// version must match every applied criterion to be removed.
+type PrunePolicy struct {
+	// Keep protects the newest Keep versions by semantic version
+	Keep int
+	// OlderThan selects versions installed longer ago than this
+	OlderThan time.Duration
+	// UnusedSince selects versions not made active within this period
+	UnusedSince time.Duration
+	// Protect lists versions that are never removed
+	Protect []string
+}
+
+// IsZero reports whether the policy has no criteria and would remove every version
+func (p PrunePolicy) IsZero() bool {
+	return p.Keep <= 0 && p.OlderThan <= 0 && p.UnusedSince <= 0
+}
+
+// PrunePolicyFromConfig builds the policy described by the autoPrune configuration
+func PrunePolicyFromConfig(cfg config.AutoPruneConfig) (PrunePolicy, error) {
+	policy := PrunePolicy{Keep: cfg.Keep}
+	var err error
+	if cfg.OlderThan != "" {
+		if policy.OlderThan, err = ParseAge(cfg.OlderThan); err != nil {
+			return policy, fmt.Errorf("invalid autoPrune.olderThan: %w", err)
+		}
+	}
+	if cfg.UnusedSince != "" {
+		if policy.UnusedSince, err = ParseAge(cfg.UnusedSince); err != nil {
+			return policy, fmt.Errorf("invalid autoPrune.unusedSince: %w", err)
+		}
+	}
+	return policy, nil
+}
+
+// ParseAge parses a duration that, besides the units understood by time.ParseDuration,
+// may be given in days or weeks, such as 90d or 2w
+func ParseAge(s string) (time.Duration, error) {
+	for suffix, unit := range map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour} {
+		if n, ok := strings.CutSuffix(s, suffix); ok {
+			count, err := strconv.Atoi(n)
+			if err != nil || count < 0 {
+				return 0, fmt.Errorf("invalid duration %q", s)
+			}
+			return time.Duration(count) * unit, nil
+		}
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid duration %q: use a number followed by d, w, h or m, such as 90d", s)
+	}
+	return d, nil
+}
+
+// Prune removes the installed versions selected by policy and returns them in ascending
+// order. The active version, held versions and development builds are never removed. With dryRun,
+// the versions that would be removed are returned without removing them.
+func (m *Manager) Prune(policy PrunePolicy, dryRun bool) ([]string, error) {
+	if policy.IsZero() {
+		return nil, fmt.Errorf("no retention policy given; use --keep, --older-than or --unused-since")
+	}
+
+	unlock, err := m.lock()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	return m.prune(policy, dryRun)
+}
+
+// PruneExcept removes the installed versions not listed in keep and returns them in
+// ascending order. The active version, held versions and development builds are never
+// removed. With dryRun, the versions that would be removed are returned without removing them.
+func (m *Manager) PruneExcept(keep []string, dryRun bool) ([]string, error) {
+	unlock, err := m.lock()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	return m.prune(PrunePolicy{Protect: keep}, dryRun)
+}
+
+// prune removes the installed versions selected by policy. The caller must hold the lock.
+func (m *Manager) prune(policy PrunePolicy, dryRun bool) ([]string, error) {
+	installed, err := m.InstalledVersions()
+	if err != nil {
+		return nil, err
+	}
+	active, err := m.ActiveVersion()
+	if err != nil {
+		return nil, err
+	}
+	reg, err := m.loadRegistry()
+	if err != nil {
+		return nil, err
+	}
+
+	protected := map[string]bool{active: true, "develop": true}
+	for _, version := range policy.Protect {
+		protected[version] = true
+	}
+	for version := range reg.Held {
+		protected[version] = true
+	}
+
+	// Walk from the newest version down, so that the first Keep versions are retained
+	semver.Sort(installed)
+	now := time.Now()
+	var selected []string
+	for i := len(installed) - 1; i >= 0; i-- {
+		version := installed[i]
+		if len(installed)-1-i < policy.Keep || protected[version] {
+			continue
+		}
+
+		installedAt := m.installTime(reg, version)
+		if policy.OlderThan > 0 && now.Sub(installedAt) < policy.OlderThan {
+			continue
+		}
+		if policy.UnusedSince > 0 {
+			lastUsed, ok := reg.LastUsed[version]
+			if !ok {
+				lastUsed = installedAt
+			}
+			if now.Sub(lastUsed) < policy.UnusedSince {
+				continue
+			}
+		}
+		selected = append(selected, version)
+	}
+	semver.Sort(selected)
+
+	if dryRun || len(selected) == 0 {
+		return selected, nil
+	}
+
+	for _, version := range selected {
+		if err := m.fs.Remove(m.binaryPath(version)); err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to remove version %s: %w", version, err)
+		}
+		delete(reg.Versions, version)
+		delete(reg.LastUsed, version)
+	}
+	if err := m.saveRegistry(reg); err != nil {
+		return nil, err
+	}
+	return selected, nil
+}
+
+// autoPrune applies the autoPrune configuration after installing versions. The caller must
+// hold the lock.
+func (m *Manager) autoPrune(versions ...string) error {
+	if !m.config.AutoPrune.Enabled {
+		return nil
+	}
+
+	policy, err := PrunePolicyFromConfig(m.config.AutoPrune)
+	if err != nil {
+		return err
+	}
+	if policy.IsZero() {
+		return fmt.Errorf("autoPrune is enabled but sets none of keep, olderThan or unusedSince")
+	}
+	// The versions just installed are never pruned, even if older than the ones kept
+	policy.Protect = append(policy.Protect, versions...)
+
+	removed, err := m.prune(policy, false)
+	if err != nil {
+		return fmt.Errorf("installed %s but auto-prune failed: %w", strings.Join(versions, ", "), err)
+	}
+	if len(removed) > 0 {
+		fmt.Printf("Auto-pruned versions: %s\n", strings.Join(removed, ", "))
+	}
+	return nil
+}
+
+// warnAutoPrune applies the auto-prune policy after versions were installed, printing a
+// failure as a warning, as the installs succeeded regardless
+func (m *Manager) warnAutoPrune(versions ...string) {
+	if err := m.autoPrune(versions...); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+}
+
+// installTime returns when a version was installed, falling back to the binary's
+// modification time for versions installed before install records were kept
+func (m *Manager) installTime(reg *registry, version string) time.Time {
+	if record, ok := reg.Versions[version]; ok && !record.InstalledAt.IsZero() {
+		return record.InstalledAt
+	}
+	if fi, err := m.fs.Stat(m.binaryPath(version)); err == nil {
+		return fi.ModTime()
+	}
+	return time.Now()
+}
@@ -0,0 +1,165 @@
+package version
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/educates/educatesenv/pkg/config"
+)
+
+func TestParseAge(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected time.Duration
+		wantErr  bool
+	}{
+		{"90d", 90 * 24 * time.Hour, false},
+		{"2w", 14 * 24 * time.Hour, false},
+		{"12h", 12 * time.Hour, false},
+		{"1h30m", 90 * time.Minute, false},
+		{"d", 0, true},
+		{"-1d", 0, true},
+		{"soon", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			d, err := ParseAge(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, d)
+		})
+	}
+}
+
+// installForPrune creates installed versions with records installed and last used the
+// given number of days ago
+func installForPrune(t *testing.T, manager *Manager, binDir string, versions map[string][2]int) {
+	reg, err := manager.loadRegistry()
+	assert.NoError(t, err)
+	for version, days := range versions {
+		err := afero.WriteFile(manager.fs, filepath.Join(binDir, "educates-"+version+".exe"), []byte(version), 0o755)
+		assert.NoError(t, err)
+		reg.Versions[version] = &InstallRecord{Version: version, InstalledAt: time.Now().AddDate(0, 0, -days[0])}
+		if days[1] >= 0 {
+			reg.LastUsed[version] = time.Now().AddDate(0, 0, -days[1])
+		}
+	}
+	assert.NoError(t, manager.saveRegistry(reg))
+}
+
+func TestPrune(t *testing.T) {
+	manager, binDir := setupWindowsManager(t, afero.NewMemMapFs())
+
+	// version: {installed days ago, last used days ago or -1 if never}
+	installForPrune(t, manager, binDir, map[string][2]int{
+		"3.0.0":  {200, 150},
+		"3.1.0":  {120, 5},
+		"3.2.0":  {100, -1},
+		"3.2.1":  {10, -1},
+		"3.10.0": {1, 1},
+	})
+	assert.NoError(t, manager.UseVersion("3.0.0"))
+
+	_, err := manager.Prune(PrunePolicy{}, true)
+	assert.Error(t, err)
+
+	// Keep compares by semantic version and never removes the active version
+	removed, err := manager.Prune(PrunePolicy{Keep: 2}, true)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"3.1.0", "3.2.0"}, removed)
+
+	removed, err = manager.Prune(PrunePolicy{OlderThan: 90 * 24 * time.Hour}, true)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"3.1.0", "3.2.0"}, removed)
+
+	// Versions never used count from their install time
+	removed, err = manager.Prune(PrunePolicy{UnusedSince: 30 * 24 * time.Hour}, true)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"3.2.0"}, removed)
+
+	// Held versions are protected too
+	assert.NoError(t, manager.SetHeld("3.2.0", true))
+	removed, err = manager.Prune(PrunePolicy{Keep: 2}, true)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"3.1.0"}, removed)
+	assert.NoError(t, manager.SetHeld("3.2.0", false))
+
+	// A dry run removes nothing
+	versions, err := manager.InstalledVersions()
+	assert.NoError(t, err)
+	assert.Len(t, versions, 5)
+
+	removed, err = manager.Prune(PrunePolicy{Keep: 1, OlderThan: 90 * 24 * time.Hour, Protect: []string{"3.1.0"}}, false)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"3.2.0"}, removed)
+
+	versions, err = manager.InstalledVersions()
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"3.0.0", "3.1.0", "3.2.1", "3.10.0"}, versions)
+	records, err := manager.InstallRecords()
+	assert.NoError(t, err)
+	assert.NotContains(t, records, "3.2.0")
+}
+
+func TestPruneExcept(t *testing.T) {
+	manager, binDir := setupWindowsManager(t, afero.NewMemMapFs())
+	installForPrune(t, manager, binDir, map[string][2]int{
+		"3.0.0": {200, -1},
+		"3.1.0": {120, -1},
+		"3.2.0": {100, -1},
+		"3.2.1": {10, -1},
+	})
+	assert.NoError(t, manager.UseVersion("3.0.0"))
+	assert.NoError(t, manager.SetHeld("3.1.0", true))
+
+	// The active and held versions stay, even if not listed
+	removed, err := manager.PruneExcept([]string{"3.2.1"}, true)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"3.2.0"}, removed)
+
+	removed, err = manager.PruneExcept([]string{"3.2.1"}, false)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"3.2.0"}, removed)
+	versions, err := manager.InstalledVersions()
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"3.0.0", "3.1.0", "3.2.1"}, versions)
+}
+
+func TestUseVersionRecordsLastUsed(t *testing.T) {
+	manager, binDir := setupWindowsManager(t, afero.NewMemMapFs())
+	installForPrune(t, manager, binDir, map[string][2]int{"3.2.1": {10, -1}})
+
+	assert.NoError(t, manager.UseVersion("3.2.1"))
+	reg, err := manager.loadRegistry()
+	assert.NoError(t, err)
+	assert.WithinDuration(t, time.Now(), reg.LastUsed["3.2.1"], time.Minute)
+}
+
+func TestPrunePolicyFromConfig(t *testing.T) {
+	policy, err := PrunePolicyFromConfig(config.AutoPruneConfig{Enabled: true, Keep: 3, UnusedSince: "30d"})
+	assert.NoError(t, err)
+	assert.Equal(t, PrunePolicy{Keep: 3, UnusedSince: 30 * 24 * time.Hour}, policy)
+
+	_, err = PrunePolicyFromConfig(config.AutoPruneConfig{Enabled: true, OlderThan: "soon"})
+	assert.Error(t, err)
+}
+
+func TestAutoPruneFailureAfterInstall(t *testing.T) {
+	manager, _, cleanup := setupTestManager(t)
+	defer cleanup()
+	assert.NoError(t, afero.WriteFile(manager.fs, manager.binaryPath("3.2.1"), []byte("binary"), 0o755))
+
+	// A policy that cannot be applied fails the prune, but not the install before it
+	manager.config.AutoPrune = config.AutoPruneConfig{Enabled: true}
+	assert.ErrorContains(t, manager.autoPrune("3.2.1"), "sets none of keep, olderThan or unusedSince")
+	assert.NoError(t, manager.InstallVersion("3.2.1", false, false))
+	assert.True(t, manager.IsInstalled("3.2.1"))
+}
@@ -0,0 +1,207 @@
+package version
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/spf13/afero"
+)
+
+// registryFileName is the file in the bin directory that records how each version was installed
+const registryFileName = "installed.json"
+
+// Verification statuses reported by Verify
+const (
+	// VerifyOK means the binary matches its recorded checksum
+	VerifyOK = "ok"
+	// VerifyModified means the binary no longer matches its recorded checksum
+	VerifyModified = "modified"
+	// VerifyMissing means a version is recorded but its binary is gone
+	VerifyMissing = "missing"
+	// VerifyUnrecorded means a binary is installed without a record, e.g. from before records were kept
+	VerifyUnrecorded = "unrecorded"
+)
+
+// InstallRecord describes the provenance of an installed version
+type InstallRecord struct {
+	Version            string    `json:"version"`
+	Source             string    `json:"source"`
+	Asset              string    `json:"asset"`
+	SHA256             string    `json:"sha256"`
+	Size               int64     `json:"size"`
+	Signature          string    `json:"signature,omitempty"`
+	InstalledAt        time.Time `json:"installedAt"`
+	Overwrite          bool      `json:"overwrite"`
+	EducatesenvVersion string    `json:"educatesenvVersion"`
+	// Imported is how a local file was imported, ImportCopy or ImportLink, and empty for downloads
+	Imported string `json:"imported,omitempty"`
+}
+
+// VerifyResult is the outcome of verifying one installed version
+type VerifyResult struct {
+	Version  string
+	Status   string
+	Expected string
+	Actual   string
+}
+
+// registry is the on-disk format of the install registry
+type registry struct {
+	Versions map[string]*InstallRecord `json:"versions"`
+	LastUsed map[string]time.Time      `json:"lastUsed,omitempty"`
+	Held     map[string]bool           `json:"held,omitempty"`
+}
+
+// InstallRecords returns the install records of all versions, keyed by version
+func (m *Manager) InstallRecords() (map[string]*InstallRecord, error) {
+	reg, err := m.loadRegistry()
+	if err != nil {
+		return nil, err
+	}
+	return reg.Versions, nil
+}
+
+// Verify re-hashes the given installed versions, or all installed and recorded versions
+// if none are given, and compares them with their install records
+func (m *Manager) Verify(versions ...string) ([]VerifyResult, error) {
+	reg, err := m.loadRegistry()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(versions) == 0 {
+		installed, err := m.InstalledVersions()
+		if err != nil {
+			return nil, err
+		}
+		seen := map[string]bool{}
+		for _, version := range installed {
+			seen[version] = true
+			versions = append(versions, version)
+		}
+		for version := range reg.Versions {
+			if !seen[version] {
+				versions = append(versions, version)
+			}
+		}
+		sort.Strings(versions)
+	}
+
+	var results []VerifyResult
+	for _, version := range versions {
+		result := VerifyResult{Version: version}
+		record, recorded := reg.Versions[version]
+		if recorded {
+			result.Expected = record.SHA256
+		}
+
+		digest, _, err := m.hashFile(m.binaryPath(version))
+		switch {
+		case os.IsNotExist(err):
+			result.Status = VerifyMissing
+		case err != nil:
+			return nil, fmt.Errorf("failed to hash version %s: %w", version, err)
+		case !recorded:
+			result.Actual = digest
+			result.Status = VerifyUnrecorded
+		case digest != record.SHA256:
+			result.Actual = digest
+			result.Status = VerifyModified
+		default:
+			result.Actual = digest
+			result.Status = VerifyOK
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// recordInstall stores the install record of a version. The caller must hold the lock.
+func (m *Manager) recordInstall(record *InstallRecord) error {
+	reg, err := m.loadRegistry()
+	if err != nil {
+		return err
+	}
+	reg.Versions[record.Version] = record
+	return m.saveRegistry(reg)
+}
+
+// recordUse stores the time a version was last made active. The caller must hold the lock.
+func (m *Manager) recordUse(version string) error {
+	reg, err := m.loadRegistry()
+	if err != nil {
+		return err
+	}
+	reg.LastUsed[version] = time.Now().UTC()
+	return m.saveRegistry(reg)
+}
+
+// registryPath returns the path of the install registry
+func (m *Manager) registryPath() string {
+	return filepath.Join(m.config.Local.Dir, registryFileName)
+}
+
+// loadRegistry reads the install registry, returning an empty one if it does not exist
+func (m *Manager) loadRegistry() (*registry, error) {
+	reg := &registry{Versions: map[string]*InstallRecord{}, LastUsed: map[string]time.Time{}, Held: map[string]bool{}}
+	data, err := afero.ReadFile(m.fs, m.registryPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return reg, nil
+		}
+		return nil, fmt.Errorf("failed to read install registry: %w", err)
+	}
+	if err := json.Unmarshal(data, reg); err != nil {
+		return nil, fmt.Errorf("failed to parse install registry %s: %w", m.registryPath(), err)
+	}
+	if reg.Versions == nil {
+		reg.Versions = map[string]*InstallRecord{}
+	}
+	if reg.LastUsed == nil {
+		reg.LastUsed = map[string]time.Time{}
+	}
+	if reg.Held == nil {
+		reg.Held = map[string]bool{}
+	}
+	return reg, nil
+}
+
+// saveRegistry writes the install registry atomically. The caller must hold the lock.
+func (m *Manager) saveRegistry(reg *registry) error {
+	data, err := json.MarshalIndent(reg, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode install registry: %w", err)
+	}
+	tmpPath := m.registryPath() + ".tmp"
+	if err := afero.WriteFile(m.fs, tmpPath, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write install registry: %w", err)
+	}
+	if err := m.fs.Rename(tmpPath, m.registryPath()); err != nil {
+		_ = m.fs.Remove(tmpPath)
+		return fmt.Errorf("failed to write install registry: %w", err)
+	}
+	return nil
+}
+
+// hashFile returns the hex encoded SHA-256 digest and size of a file
+func (m *Manager) hashFile(path string) (string, int64, error) {
+	f, err := m.fs.Open(path)
+	if err != nil {
+		return "", 0, err
+	}
+	defer func() { _ = f.Close() }()
+
+	h := sha256.New()
+	size, err := io.Copy(h, f)
+	if err != nil {
+		return "", 0, err
+	}
+	return hex.EncodeToString(h.Sum(nil)), size, nil
+}
@@ -0,0 +1,69 @@
+package version
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerify(t *testing.T) {
+	manager, binDir := setupWindowsManager(t, afero.NewMemMapFs())
+
+	// Record two installed versions
+	for _, version := range []string{"v1.0.0", "v2.0.0"} {
+		path := filepath.Join(binDir, "educates-"+version+".exe")
+		err := afero.WriteFile(manager.fs, path, []byte("binary "+version), 0o755)
+		assert.NoError(t, err)
+
+		digest, size, err := manager.hashFile(path)
+		assert.NoError(t, err)
+		err = manager.recordInstall(&InstallRecord{
+			Version:     version,
+			Source:      "https://example.com/" + version,
+			SHA256:      digest,
+			Size:        size,
+			InstalledAt: time.Now(),
+		})
+		assert.NoError(t, err)
+	}
+
+	records, err := manager.InstallRecords()
+	assert.NoError(t, err)
+	assert.Len(t, records, 2)
+	assert.Equal(t, int64(len("binary v1.0.0")), records["v1.0.0"].Size)
+
+	results, err := manager.Verify()
+	assert.NoError(t, err)
+	assert.Len(t, results, 2)
+	for _, result := range results {
+		assert.Equal(t, VerifyOK, result.Status)
+	}
+
+	// Tamper with one binary, remove the other and add an unrecorded one
+	err = afero.WriteFile(manager.fs, filepath.Join(binDir, "educates-v1.0.0.exe"), []byte("tampered"), 0o755)
+	assert.NoError(t, err)
+	err = manager.fs.Remove(filepath.Join(binDir, "educates-v2.0.0.exe"))
+	assert.NoError(t, err)
+	err = afero.WriteFile(manager.fs, filepath.Join(binDir, "educates-v3.0.0.exe"), []byte("binary v3.0.0"), 0o755)
+	assert.NoError(t, err)
+
+	results, err = manager.Verify()
+	assert.NoError(t, err)
+	statuses := map[string]string{}
+	for _, result := range results {
+		statuses[result.Version] = result.Status
+	}
+	assert.Equal(t, map[string]string{
+		"v1.0.0": VerifyModified,
+		"v2.0.0": VerifyMissing,
+		"v3.0.0": VerifyUnrecorded,
+	}, statuses)
+
+	// Specific versions can be verified
+	results, err = manager.Verify("v3.0.0")
+	assert.NoError(t, err)
+	assert.Len(t, results, 1)
+}
@@ -0,0 +1,96 @@
+package version
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/educates/educatesenv/pkg/semver"
+)
+
+// Binary returns the path of the binary for an installed version, or of the development
+// binary for "develop" and dev:<name>
+func (m *Manager) Binary(version string) (string, error) {
+	binary := m.binaryPath(version)
+
+	// Handle development builds
+	if IsDevVersion(version) {
+		var err error
+		if binary, err = m.devBinary(version); err != nil {
+			return "", err
+		}
+	}
+
+	if _, err := m.fs.Stat(binary); err != nil {
+		if os.IsNotExist(err) {
+			return "", fmt.Errorf("binary not found at %s", binary)
+		}
+		return "", fmt.Errorf("failed to check binary: %w", err)
+	}
+	return binary, nil
+}
+
+// ResolveInstalled returns the installed version selected by spec, which is a version,
+// a development build, an alias, or a constraint such as ~3.2 that selects the highest matching
+// installed version. It reports false if no installed version matches.
+func (m *Manager) ResolveInstalled(spec string) (string, bool, error) {
+	spec, err := m.ResolveAlias(spec)
+	if err != nil {
+		return "", false, err
+	}
+	if IsDevVersion(spec) {
+		return spec, true, nil
+	}
+	installed, err := m.InstalledVersions()
+	if err != nil {
+		return "", false, err
+	}
+	return MatchVersion(spec, installed)
+}
+
+// MatchVersion returns the version in versions selected by spec. A spec naming one of the
+// versions exactly selects it, otherwise spec is a constraint and selects the highest
+// version satisfying it.
+func MatchVersion(spec string, versions []string) (string, bool, error) {
+	if slices.Contains(versions, spec) {
+		return spec, true, nil
+	}
+	constraint, err := semver.ParseConstraint(spec)
+	if err != nil {
+		return "", false, err
+	}
+	version, ok := constraint.Latest(versions)
+	return version, ok, nil
+}
+
+// ResolveRelease returns the release selected by spec. An alias is replaced by its target,
+// and a constraint selects the newest release satisfying it, listing the release tags with
+// listTags. An exact version is returned as is, without listing releases, and so is a spec
+// that is not a version or constraint, which is taken as a literal release tag.
+func (m *Manager) ResolveRelease(spec string, listTags func() ([]string, error)) (string, error) {
+	target, err := m.ResolveAlias(spec)
+	if err != nil {
+		return "", err
+	}
+	if IsDevVersion(target) {
+		return "", fmt.Errorf("%s refers to a development build, which cannot be installed", spec)
+	}
+	constraint, err := semver.ParseConstraint(target)
+	if err != nil {
+		return target, nil
+	}
+	if constraint.IsExact() {
+		return strings.TrimPrefix(target, "="), nil
+	}
+
+	tags, err := listTags()
+	if err != nil {
+		return "", err
+	}
+	latest, ok := constraint.Latest(tags)
+	if !ok {
+		return "", fmt.Errorf("no release matches %s", target)
+	}
+	return latest, nil
+}
@@ -0,0 +1,98 @@
+package version
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveInstalled(t *testing.T) {
+	manager, tmpDir, cleanup := setupTestManager(t)
+	defer cleanup()
+
+	for _, version := range []string{"3.1.4", "3.2.0", "3.2.3", "3.3.0-rc.1"} {
+		err := os.WriteFile(filepath.Join(tmpDir, "educates-"+version), []byte("test binary"), 0o755)
+		assert.NoError(t, err)
+	}
+
+	tests := []struct {
+		spec    string
+		want    string
+		matched bool
+	}{
+		{"3.2.0", "3.2.0", true},
+		{"3.3.0-rc.1", "3.3.0-rc.1", true},
+		{"~3.2", "3.2.3", true},
+		{">=3.0.0", "3.2.3", true},
+		{"3.1", "3.1.4", true},
+		{"4", "", false},
+		{"develop", "develop", true},
+	}
+	for _, tt := range tests {
+		version, ok, err := manager.ResolveInstalled(tt.spec)
+		assert.NoError(t, err, tt.spec)
+		assert.Equal(t, tt.matched, ok, tt.spec)
+		assert.Equal(t, tt.want, version, tt.spec)
+	}
+
+	_, _, err := manager.ResolveInstalled("not-a-version")
+	assert.Error(t, err)
+}
+
+func TestResolveRelease(t *testing.T) {
+	manager, _, cleanup := setupTestManager(t)
+	defer cleanup()
+	assert.NoError(t, manager.SetAlias("stable", "~3.2"))
+
+	listed := 0
+	listTags := func() ([]string, error) {
+		listed++
+		return []string{"3.1.4", "3.2.0", "3.2.3", "3.3.0-rc.1", "nightly-20250101"}, nil
+	}
+
+	tests := []struct {
+		spec   string
+		want   string
+		listed bool
+	}{
+		{"3.2.0", "3.2.0", false},
+		{"=3.2.0", "3.2.0", false},
+		{"~3.2", "3.2.3", true},
+		{"stable", "3.2.3", true},
+		// A tag that is not a version or constraint is taken literally
+		{"nightly-20250101", "nightly-20250101", false},
+		{"v3.2.0-custom+build", "v3.2.0-custom+build", false},
+	}
+	for _, tt := range tests {
+		listed = 0
+		version, err := manager.ResolveRelease(tt.spec, listTags)
+		assert.NoError(t, err, tt.spec)
+		assert.Equal(t, tt.want, version, tt.spec)
+		assert.Equal(t, tt.listed, listed > 0, tt.spec)
+	}
+
+	_, err := manager.ResolveRelease("~4", listTags)
+	assert.EqualError(t, err, "no release matches ~4")
+	_, err = manager.ResolveRelease("dev:main", listTags)
+	assert.ErrorContains(t, err, "development build")
+}
+
+func TestBinary(t *testing.T) {
+	manager, tmpDir, cleanup := setupTestManager(t)
+	defer cleanup()
+
+	binaryPath := filepath.Join(tmpDir, "educates-3.2.0")
+	assert.NoError(t, os.WriteFile(binaryPath, []byte("test binary"), 0o755))
+
+	binary, err := manager.Binary("3.2.0")
+	assert.NoError(t, err)
+	assert.Equal(t, binaryPath, binary)
+
+	_, err = manager.Binary("3.1.0")
+	assert.ErrorContains(t, err, "binary not found")
+
+	_, err = manager.Binary("develop")
+	assert.ErrorContains(t, err, "development mode is not enabled")
+}
@@ -0,0 +1,92 @@
+package version
+
+import (
+	"fmt"
+
+	"github.com/spf13/afero"
+
+	"github.com/educates/educatesenv/pkg/verify"
+)
+
+// verifySignature checks a downloaded binary, with the given digest, against a signature
+// published in the release, either over the binary itself or over the release checksum
+// file. Verification happens offline against the public keys in verify.publicKeys. It
+// returns the name of the verified signature asset, or an empty string if no signature was
+// checked, which is reported as a warning. With verify.required set, a missing signature is
+// an error.
+func (m *Manager) verifySignature(dl *pendingDownload, digest string, report func(string)) (string, error) {
+	version, assetName, binaryPath, assets := dl.version, dl.assetName, dl.downloadPath, dl.assets
+	cfg := m.config.Verify
+	if len(cfg.PublicKeys) == 0 {
+		if cfg.Required {
+			return "", fmt.Errorf("verify.required is set but no verify.publicKeys are configured")
+		}
+		return "", nil
+	}
+
+	verifier, err := verify.NewVerifier(cfg.PublicKeys)
+	if err != nil {
+		return "", fmt.Errorf("failed to load verify.publicKeys: %w", err)
+	}
+
+	// Prefer a signature over the binary itself
+	for _, suffix := range verify.Suffixes() {
+		sigName := assetName + suffix
+		sigURL, ok := assets[sigName]
+		if !ok {
+			continue
+		}
+		sig, err := m.github.DownloadAsset(sigURL)
+		if err != nil {
+			return "", err
+		}
+		binary, err := afero.ReadFile(m.fs, binaryPath)
+		if err != nil {
+			return "", fmt.Errorf("failed to read downloaded binary: %w", err)
+		}
+		if err := verifier.Verify(suffix, binary, sig); err != nil {
+			return "", fmt.Errorf("signature %s does not verify %s: %w", sigName, assetName, err)
+		}
+		return sigName, nil
+	}
+
+	// Otherwise accept a signed checksum file that lists the binary
+	for _, sumsName := range verify.ChecksumFileNames {
+		sumsURL, ok := assets[sumsName]
+		if !ok {
+			continue
+		}
+		for _, suffix := range verify.Suffixes() {
+			sigName := sumsName + suffix
+			sigURL, ok := assets[sigName]
+			if !ok {
+				continue
+			}
+			sums, err := m.github.DownloadAsset(sumsURL)
+			if err != nil {
+				return "", err
+			}
+			sig, err := m.github.DownloadAsset(sigURL)
+			if err != nil {
+				return "", err
+			}
+			if err := verifier.Verify(suffix, sums, sig); err != nil {
+				return "", fmt.Errorf("signature %s does not verify %s: %w", sigName, sumsName, err)
+			}
+			expected, ok := verify.LookupChecksum(sums, assetName)
+			if !ok {
+				return "", fmt.Errorf("signed checksum file %s does not list %s", sumsName, assetName)
+			}
+			if expected != digest {
+				return "", fmt.Errorf("checksum mismatch for %s: %s lists %s, downloaded file has %s", assetName, sumsName, expected, digest)
+			}
+			return sigName, nil
+		}
+	}
+
+	if cfg.Required {
+		return "", fmt.Errorf("release %s has no signature for %s or a checksum file, and verify.required is set", version, assetName)
+	}
+	report(fmt.Sprintf("Warning: release %s has no signature for %s; skipping signature verification", version, assetName))
+	return "", nil
+}
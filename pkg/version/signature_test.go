@@ -0,0 +1,118 @@
+package version
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerifySignature(t *testing.T) {
+	manager, tmpDir, cleanup := setupTestManager(t)
+	defer cleanup()
+
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(&priv.PublicKey)
+	assert.NoError(t, err)
+	publicKey := string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+	sign := func(message []byte) []byte {
+		digest := sha256.Sum256(message)
+		sig, err := ecdsa.SignASN1(rand.Reader, priv, digest[:])
+		assert.NoError(t, err)
+		return []byte(base64.StdEncoding.EncodeToString(sig))
+	}
+
+	binary := []byte("educates binary")
+	binaryPath := filepath.Join(tmpDir, ".educates-3.2.1.download")
+	assert.NoError(t, os.WriteFile(binaryPath, binary, 0o755))
+	digest := sha256Hex(binary)
+	checksums := []byte(digest + "  educates-linux-amd64\n")
+
+	files := map[string][]byte{
+		"/good.sig":          sign(binary),
+		"/bad.sig":           sign([]byte("another binary")),
+		"/checksums.txt":     checksums,
+		"/checksums.txt.sig": sign(checksums),
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, ok := files[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write(data)
+	}))
+	defer server.Close()
+
+	verify := func(assets map[string]string) (string, []string, error) {
+		var messages []string
+		dl := &pendingDownload{version: "3.2.1", assetName: "educates-linux-amd64", downloadPath: binaryPath, assets: assets}
+		signature, err := manager.verifySignature(dl, digest, func(message string) { messages = append(messages, message) })
+		return signature, messages, err
+	}
+	noSignature := map[string]string{"educates-linux-amd64": server.URL + "/binary"}
+
+	// Without keys, nothing is checked unless verification is required
+	signature, _, err := verify(noSignature)
+	assert.NoError(t, err)
+	assert.Empty(t, signature)
+	manager.config.Verify.Required = true
+	_, _, err = verify(noSignature)
+	assert.ErrorContains(t, err, "no verify.publicKeys are configured")
+
+	manager.config.Verify.PublicKeys = []string{publicKey}
+
+	// A signature over the binary is preferred
+	signature, _, err = verify(map[string]string{
+		"educates-linux-amd64.sig": server.URL + "/good.sig",
+		"checksums.txt":            server.URL + "/checksums.txt",
+		"checksums.txt.sig":        server.URL + "/bad.sig",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "educates-linux-amd64.sig", signature)
+
+	// A signed checksum file listing the binary is accepted
+	signature, _, err = verify(map[string]string{
+		"checksums.txt":     server.URL + "/checksums.txt",
+		"checksums.txt.sig": server.URL + "/checksums.txt.sig",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "checksums.txt.sig", signature)
+
+	// A bad signature always fails, whether or not verification is required
+	for _, required := range []bool{true, false} {
+		manager.config.Verify.Required = required
+		_, _, err = verify(map[string]string{"educates-linux-amd64.sig": server.URL + "/bad.sig"})
+		assert.ErrorContains(t, err, "signature educates-linux-amd64.sig does not verify educates-linux-amd64")
+		_, _, err = verify(map[string]string{
+			"checksums.txt":     server.URL + "/checksums.txt",
+			"checksums.txt.sig": server.URL + "/bad.sig",
+		})
+		assert.ErrorContains(t, err, "signature checksums.txt.sig does not verify checksums.txt")
+	}
+
+	// A missing signature fails closed when verification is required
+	manager.config.Verify.Required = true
+	_, _, err = verify(noSignature)
+	assert.ErrorContains(t, err, "verify.required is set")
+	manager.config.Verify.Required = false
+	signature, messages, err := verify(noSignature)
+	assert.NoError(t, err)
+	assert.Empty(t, signature)
+	assert.Equal(t, []string{"Warning: release 3.2.1 has no signature for educates-linux-amd64; skipping signature verification"}, messages)
+
+	// A signature that cannot be downloaded is an error
+	_, _, err = verify(map[string]string{"educates-linux-amd64.sig": server.URL + "/missing.sig"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,115 @@
+package version
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/educates/educatesenv/pkg/semver"
+)
+
+// Upgrade levels, from the most to the least conservative
+const (
+	UpgradePatch = "patch"
+	UpgradeMinor = "minor"
+	UpgradeMajor = "major"
+)
+
+// Upgrades holds the newest releases available for a version. A field is empty if there
+// is no newer release at that level.
+type Upgrades struct {
+	// Patch is the newest release with the same major and minor version
+	Patch string
+	// Minor is the newest release with the same major version
+	Minor string
+	// Major is the newest release
+	Major string
+}
+
+// Level returns the upgrade at the given level
+func (u Upgrades) Level(level string) (string, error) {
+	switch level {
+	case UpgradePatch:
+		return u.Patch, nil
+	case UpgradeMinor:
+		return u.Minor, nil
+	case UpgradeMajor:
+		return u.Major, nil
+	default:
+		return "", fmt.Errorf("invalid upgrade level %q", level)
+	}
+}
+
+// AvailableUpgrades returns the newest releases newer than current at each level.
+// Pre-releases and releases that are not semantic versions are never offered.
+func AvailableUpgrades(current string, releases []string) (Upgrades, error) {
+	cv, err := semver.Parse(current)
+	if err != nil {
+		return Upgrades{}, err
+	}
+
+	var newer []*semver.Version
+	for _, release := range releases {
+		v, err := semver.Parse(release)
+		if err != nil || v.IsPrerelease() || v.Compare(cv) <= 0 {
+			continue
+		}
+		newer = append(newer, v)
+	}
+	// Walk from the newest down, so that the first match at each level is the newest
+	sort.Slice(newer, func(i, j int) bool { return newer[i].Compare(newer[j]) > 0 })
+
+	var upgrades Upgrades
+	for _, v := range newer {
+		if upgrades.Major == "" {
+			upgrades.Major = v.String()
+		}
+		if upgrades.Minor == "" && v.Major == cv.Major {
+			upgrades.Minor = v.String()
+		}
+		if upgrades.Patch == "" && v.Major == cv.Major && v.Minor == cv.Minor {
+			upgrades.Patch = v.String()
+		}
+	}
+	return upgrades, nil
+}
+
+// IsHeld reports whether a version is held, which keeps it from being upgraded or pruned
+func (m *Manager) IsHeld(version string) (bool, error) {
+	reg, err := m.loadRegistry()
+	if err != nil {
+		return false, err
+	}
+	return reg.Held[version], nil
+}
+
+// HeldVersions returns the held versions
+func (m *Manager) HeldVersions() (map[string]bool, error) {
+	reg, err := m.loadRegistry()
+	if err != nil {
+		return nil, err
+	}
+	return reg.Held, nil
+}
+
+// SetHeld holds or releases an installed version
+func (m *Manager) SetHeld(version string, held bool) error {
+	unlock, err := m.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if held && !m.IsInstalled(version) {
+		return fmt.Errorf("version %s is not installed", version)
+	}
+	reg, err := m.loadRegistry()
+	if err != nil {
+		return err
+	}
+	if held {
+		reg.Held[version] = true
+	} else {
+		delete(reg.Held, version)
+	}
+	return m.saveRegistry(reg)
+}
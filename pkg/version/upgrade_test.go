@@ -0,0 +1,52 @@
+package version
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAvailableUpgrades(t *testing.T) {
+	releases := []string{"3.1.0", "3.2.1", "3.2.4", "3.2.5-rc.1", "3.3.0", "3.10.2", "4.0.0", "nightly"}
+
+	upgrades, err := AvailableUpgrades("3.2.1", releases)
+	assert.NoError(t, err)
+	assert.Equal(t, Upgrades{Patch: "3.2.4", Minor: "3.10.2", Major: "4.0.0"}, upgrades)
+
+	upgrades, err = AvailableUpgrades("3.10.2", releases)
+	assert.NoError(t, err)
+	assert.Equal(t, Upgrades{Major: "4.0.0"}, upgrades)
+
+	upgrades, err = AvailableUpgrades("4.0.0", releases)
+	assert.NoError(t, err)
+	assert.Equal(t, Upgrades{}, upgrades)
+
+	level, err := Upgrades{Patch: "3.2.4"}.Level(UpgradePatch)
+	assert.NoError(t, err)
+	assert.Equal(t, "3.2.4", level)
+	_, err = Upgrades{}.Level("latest")
+	assert.Error(t, err)
+
+	_, err = AvailableUpgrades("develop", releases)
+	assert.Error(t, err)
+}
+
+func TestSetHeld(t *testing.T) {
+	manager, tmpDir, cleanup := setupTestManager(t)
+	defer cleanup()
+
+	assert.Error(t, manager.SetHeld("3.2.1", true))
+
+	assert.NoError(t, os.WriteFile(filepath.Join(tmpDir, "educates-3.2.1"), []byte("test binary"), 0o755))
+	assert.NoError(t, manager.SetHeld("3.2.1", true))
+	held, err := manager.IsHeld("3.2.1")
+	assert.NoError(t, err)
+	assert.True(t, held)
+
+	assert.NoError(t, manager.SetHeld("3.2.1", false))
+	held, err = manager.IsHeld("3.2.1")
+	assert.NoError(t, err)
+	assert.False(t, held)
+}